@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/meyermarcel/icm/configs"
+	"github.com/meyermarcel/icm/cont"
+	"github.com/meyermarcel/icm/data"
+	"github.com/spf13/cobra"
+)
+
+func newAnonymizeCmd(stdin io.Reader, writer io.Writer, config *configs.Config, ownerDecoder data.OwnerDecoder) *cobra.Command {
+	var key string
+	var keyFile string
+	var column string
+	var unregisteredOwners bool
+
+	anonymizeCmd := &cobra.Command{
+		Use:   "anonymize [FILE]...",
+		Short: "Anonymize container numbers of data sets",
+		Long: `Anonymize container numbers of data sets like CSV files or text.
+
+Every container number found in a file is replaced by a fictitious container
+number. If no file or - is specified, standard input is anonymized. Container
+numbers are found like with the extract command and the text around them
+including separators is not changed.
+
+With the --column flag only container numbers of a CSV column are replaced.
+The column is a name of the header in the first line or a number starting
+with 1. Fields are separated by semicolons or by commas if the first line
+has more commas than semicolons.
+
+The mapping to fictitious container numbers depends on the key set with the
+--key or --key-file flag. The same container number and key always result in
+the same fictitious container number, so data sets anonymized with the same
+key can be joined. Different container numbers result in different fictitious
+container numbers.
+
+The following properties are kept:
+  - Container numbers of the same owner have fictitious container numbers of
+    the same owner.
+  - The equipment category ID is not changed.
+  - The calculated check digit is not changed. Valid container numbers stay
+    valid, invalid ones stay invalid and check digit 10 stays check digit 10.
+
+With the --unregistered-owners flag registered owner codes are only mapped to
+owner codes that are not registered in the owner sources configured in
+
+  ` + ownerSourcesPath + `
+
+Some unregistered owner codes cannot be mapped in this case and result in an
+error.
+
+` + sepHelp,
+		Example: `icm anonymize --key-file secret.txt manifest.txt > manifest-anonymized.txt
+cat events.csv | icm anonymize --key 'my secret' --column container
+# Anonymize only container numbers of the fifth column
+icm anonymize --key-file secret.txt --column 5 events.csv
+# Anonymize with unregistered owner codes
+icm anonymize --key-file secret.txt --unregistered-owners events.csv`,
+		ValidArgsFunction: func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveDefault
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config.Overwrite(cmd.Flags())
+
+			if keyFile != "" {
+				b, err := os.ReadFile(keyFile)
+				if err != nil {
+					return err
+				}
+				key = strings.TrimRight(string(b), "\r\n")
+			}
+			if key == "" {
+				return errors.New("no key set with --key or --key-file")
+			}
+
+			var isRegistered func(string) bool
+			if unregisteredOwners {
+				isRegistered = func(code string) bool {
+					found, _ := ownerDecoder.Decode(code)
+					return found
+				}
+			}
+			a := &lineAnonymizer{
+				anonymizer: cont.NewAnonymizer([]byte(key), isRegistered),
+				extractor:  cont.NewExtractor(config.SepOE(), config.SepES(), config.SepSC()),
+			}
+
+			if len(args) == 0 {
+				args = []string{"-"}
+			}
+			for _, name := range args {
+				if err := anonymizeFile(a, writer, name, column, stdin); err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+			}
+			return nil
+		},
+	}
+
+	anonymizeCmd.Flags().SortFlags = false
+
+	anonymizeCmd.Flags().StringVar(&key, "key", "", "key of the mapping to fictitious container numbers")
+	anonymizeCmd.Flags().StringVar(&keyFile, "key-file", "", "file with key of the mapping to fictitious container numbers")
+	anonymizeCmd.MarkFlagsMutuallyExclusive("key", "key-file")
+	anonymizeCmd.Flags().StringVar(&column, "column", "", "name or number of the CSV column with container numbers")
+	_ = anonymizeCmd.RegisterFlagCompletionFunc("column", cobra.NoFileCompletions)
+	anonymizeCmd.Flags().BoolVar(&unregisteredOwners, "unregistered-owners", false,
+		"maps registered owner codes only to unregistered owner codes")
+	anonymizeCmd.Flags().String(configs.FlagNames.SepOE, configs.DefaultValues.SepOE,
+		"ABC(x)U1234560  (x) separates owner code and equipment category id")
+	anonymizeCmd.Flags().String(configs.FlagNames.SepES, configs.DefaultValues.SepES,
+		"ABCU(x)1234560  (x) separates equipment category id and serial number")
+	anonymizeCmd.Flags().String(configs.FlagNames.SepSC, configs.DefaultValues.SepSC,
+		"ABCU123456(x)0  (x) separates serial number and check digit")
+
+	return anonymizeCmd
+}
+
+func anonymizeFile(a *lineAnonymizer, writer io.Writer, name, column string, stdin io.Reader) error {
+	reader := stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	if column != "" {
+		return anonymizeCSV(a, writer, reader, column)
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineBytes)
+	line := 0
+	for scanner.Scan() {
+		line++
+		anonymized, err := a.anonymize(scanner.Text())
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if _, err := io.WriteString(writer, anonymized+"\n"); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// anonymizeCSV anonymizes the column of CSV records separated by semicolons or commas.
+// The column is a name of the header in the first record or a number starting with 1.
+func anonymizeCSV(a *lineAnonymizer, writer io.Writer, reader io.Reader, column string) error {
+	bufReader := bufio.NewReader(reader)
+	peek, _ := bufReader.Peek(bufReader.Size())
+	firstLine, _, _ := bytes.Cut(peek, []byte("\n"))
+	comma := ';'
+	if bytes.Count(firstLine, []byte(",")) > bytes.Count(firstLine, []byte(";")) {
+		comma = ','
+	}
+
+	csvReader := csv.NewReader(bufReader)
+	csvReader.Comma = comma
+	csvReader.FieldsPerRecord = -1
+	csvWriter := csv.NewWriter(writer)
+	csvWriter.Comma = comma
+
+	index, err := strconv.Atoi(column)
+	isName := err != nil
+	if !isName && index < 1 {
+		return fmt.Errorf("column %d is not greater than 0", index)
+	}
+	index--
+
+	for record := 1; ; record++ {
+		rec, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if record == 1 && isName {
+			index = -1
+			for i, name := range rec {
+				if strings.EqualFold(strings.TrimSpace(name), column) {
+					index = i
+					break
+				}
+			}
+			if index == -1 {
+				return fmt.Errorf("column %s is not in header", column)
+			}
+		} else if index < len(rec) {
+			rec[index], err = a.anonymize(rec[index])
+			if err != nil {
+				return fmt.Errorf("record %d: %w", record, err)
+			}
+		}
+
+		if err := csvWriter.Write(rec); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+type lineAnonymizer struct {
+	anonymizer *cont.Anonymizer
+	extractor  *cont.Extractor
+}
+
+// anonymize returns text with fictitious container numbers. Letters and digits of the
+// found container numbers are replaced, so separators are kept.
+func (a *lineAnonymizer) anonymize(text string) (string, error) {
+	b := strings.Builder{}
+	end := 0
+	for _, e := range a.extractor.Extract(text) {
+		anonymized, err := a.anonymizer.Anonymize(e.Number)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(text[end:e.Start])
+		b.WriteString(replaceChars(e.Text, e.Number.String(), anonymized.String()))
+		end = e.End
+	}
+	b.WriteString(text[end:])
+	return b.String(), nil
+}
+
+// replaceChars returns text with the characters of from replaced by the characters of to
+// in order. Other characters of text are kept.
+func replaceChars(text, from, to string) string {
+	b := []byte(text)
+	i := 0
+	for j := range b {
+		if i < len(from) && b[j] == from[i] {
+			b[j] = to[i]
+			i++
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/meyermarcel/icm/configs"
+)
+
+func Test_anonymizeCmd(t *testing.T) {
+	type flag struct {
+		name  string
+		value string
+	}
+	const text = `load MSKU 123456 5 and CMAU1639120
+MSKU 123456 5 again
+`
+	const csvText = `id,container,note
+1,MSKU 123456 5,MSKU1234565
+2,CMAU1639120,
+`
+	tests := []struct {
+		name       string
+		flags      []flag
+		stdin      string
+		wantErr    bool
+		wantWriter string
+	}{
+		{
+			"Anonymize text",
+			[]flag{{"key", "k"}},
+			text,
+			false,
+			`load RLOU 111307 5 and KAOU7039360
+RLOU 111307 5 again
+`,
+		},
+		{
+			"Anonymize text with other key",
+			[]flag{{"key", "other"}},
+			text,
+			false,
+			`load TTZU 370378 5 and REZU1697050
+TTZU 370378 5 again
+`,
+		},
+		{
+			"Anonymize CSV column by name",
+			[]flag{{"key", "k"}, {"column", "container"}},
+			csvText,
+			false,
+			`id,container,note
+1,RLOU 111307 5,MSKU1234565
+2,KAOU7039360,
+`,
+		},
+		{
+			"Anonymize CSV column by number",
+			[]flag{{"key", "k"}, {"column", "3"}},
+			csvText,
+			false,
+			`id,container,note
+1,MSKU 123456 5,RLOU1113075
+2,CMAU1639120,
+`,
+		},
+		{
+			"Anonymize only found container numbers",
+			[]flag{{"key", "k"}, {"unregistered-owners", "true"}},
+			"XABCU1234560 ABCU1234560\n",
+			false,
+			`XABCU1234560 HFJU4825870
+`,
+		},
+		{
+			"Anonymize with unregistered owner codes",
+			[]flag{{"key", "k"}, {"unregistered-owners", "true"}},
+			"ABCU1234560\n",
+			false,
+			`HFJU4825870
+`,
+		},
+		{
+			"Return error without key",
+			nil,
+			text,
+			true,
+			"",
+		},
+		{
+			"Return error for unknown column",
+			[]flag{{"key", "k"}, {"column", "unknown"}},
+			csvText,
+			true,
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+
+			config, _ := configs.ReadConfig(configs.DefaultConfig())
+
+			cmd := newAnonymizeCmd(strings.NewReader(tt.stdin), writer, config, &dummyOwnerDecodeUpdater{})
+			for _, flag := range tt.flags {
+				_ = cmd.Flags().Set(flag.name, flag.value)
+			}
+			if got := cmd.RunE(cmd, nil); (got == nil) == tt.wantErr {
+				t.Errorf("got = %v, wantErr is %v", got, tt.wantErr)
+			}
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("gotWriter = %v, want %v", gotWriter, tt.wantWriter)
+			}
+		})
+	}
+}
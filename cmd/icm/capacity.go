@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/meyermarcel/icm/configs"
+	"github.com/meyermarcel/icm/cont"
+	"github.com/spf13/cobra"
+)
+
+const capacityTotal = "total"
+
+type capacityRecord struct {
+	OwnerCode    string `json:"owner-code"`
+	Start        int    `json:"start"`
+	End          int    `json:"end"`
+	Range        int    `json:"range"`
+	Used         int    `json:"used"`
+	CheckDigit10 int    `json:"check-digit-10"`
+	ErrorProne   int    `json:"error-prone"`
+	Excluded     int    `json:"excluded"`
+	Usable       int    `json:"usable"`
+}
+
+var capacityRecordHeader = []string{
+	"owner-code", "start", "end", "range", "used",
+	"check-digit-10", "error-prone", "excluded", "usable",
+}
+
+func (c capacityRecord) record() []string {
+	return []string{
+		c.OwnerCode,
+		strconv.Itoa(c.Start),
+		strconv.Itoa(c.End),
+		strconv.Itoa(c.Range),
+		strconv.Itoa(c.Used),
+		strconv.Itoa(c.CheckDigit10),
+		strconv.Itoa(c.ErrorProne),
+		strconv.Itoa(c.Excluded),
+		strconv.Itoa(c.Usable),
+	}
+}
+
+func newCapacityCmd(writer io.Writer, config *configs.Config) *cobra.Command {
+	output := recordOutputValue{value: outputCSV}
+	owners := ownersValue{}
+	var ownerFile string
+	startValue := serialNumValue{value: 0}
+	endValue := serialNumValue{value: 999999}
+	equipCatID := "U"
+	var excludeFile string
+	var excludeCheckDigit10 bool
+	var excludeErrorProneSerialNumbers bool
+
+	capacityCmd := &cobra.Command{
+		Use:   "capacity",
+		Short: "Count usable container numbers of serial number ranges",
+		Long: `Count usable container numbers of owners in a serial number range.
+
+For every owner the count of serial numbers in the range from --start to
+--end and the counts of container numbers excluded by every exclusion rule
+are written:
+
+            used = container numbers of the --exclude-file file
+  check-digit-10 = container numbers with check digit 10
+     error-prone = container numbers with error-prone serial numbers
+
+Used container numbers must have the owner code and the equipment category
+ID of the counted container numbers. Container numbers can be counted for
+several rules. Container numbers with check digit 10 and error-prone serial
+numbers are only excluded with the --exclude-check-digit-10 and
+--exclude-error-prone-serial-numbers flags. Usable container numbers are the
+container numbers of the range without excluded ones.
+
+For one owner this is the count of container numbers 'icm generate' can
+generate with the same flags. For several owners 'icm generate' spreads the
+serial numbers of one range across all owners, so every serial number is used
+for one owner only and fewer container numbers are generated.
+
+If --start is greater than --end, the range continues at 0 after 999999.
+With several owners a last record with owner code '` + capacityTotal + `' has the sums
+of all owners, which is the count of container numbers if every owner uses
+the whole range.
+
+The results are written as CSV, JSON or newline delimited JSON.`,
+		Example: `icm capacity --owner ABC
+icm capacity --owner ABC --start 100000 --end 199999 --exclude-check-digit-10 --exclude-error-prone-serial-numbers
+# Count usable container numbers without container numbers already in use
+icm capacity --owner ABC,DEF --exclude-file fleet.csv --output json`,
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			config.Overwrite(cmd.Flags())
+
+			codes := owners.values
+			if ownerFile != "" {
+				fileCodes, err := readOwnerCodesFile(ownerFile)
+				if err != nil {
+					return err
+				}
+				codes = append(codes, fileCodes...)
+			}
+			if len(codes) == 0 {
+				return fmt.Errorf("no owner codes set with --owner or --owner-file")
+			}
+
+			if err := cont.IsEquipCatID(equipCatID); err != nil {
+				return err
+			}
+
+			var used []cont.Number
+			if excludeFile != "" {
+				var err error
+				used, err = readExcludeFile(excludeFile, config)
+				if err != nil {
+					return err
+				}
+			}
+
+			printer := newRecordPrinter(writer, output.value, config.NoHeader(), capacityRecordHeader)
+			total := capacityRecord{OwnerCode: capacityTotal, Start: startValue.value, End: endValue.value}
+			uniqueCodes := uniqueOwnerCodes(codes)
+			for _, code := range uniqueCodes {
+				c := cont.CountCapacity(code, rune(equipCatID[0]), startValue.value, endValue.value, used,
+					excludeCheckDigit10, excludeErrorProneSerialNumbers)
+				if err := printer.print(capacityRecord{
+					c.OwnerCode, startValue.value, endValue.value, c.Range, c.Used,
+					c.CheckDigit10, c.ErrorProne, c.Excluded, c.Usable,
+				}); err != nil {
+					return err
+				}
+				total.Range += c.Range
+				total.Used += c.Used
+				total.CheckDigit10 += c.CheckDigit10
+				total.ErrorProne += c.ErrorProne
+				total.Excluded += c.Excluded
+				total.Usable += c.Usable
+			}
+			if len(uniqueCodes) > 1 {
+				if err := printer.print(total); err != nil {
+					return err
+				}
+			}
+			return printer.close()
+		},
+	}
+
+	capacityCmd.Flags().SortFlags = false
+
+	capacityCmd.Flags().Var(&owners, "owner", "owner codes, repeated or separated by comma")
+	capacityCmd.Flags().StringVar(&ownerFile, "owner-file", "", "file with owner codes")
+	capacityCmd.Flags().VarP(&startValue, "start", "s", "start of serial number range")
+	capacityCmd.Flags().VarP(&endValue, "end", "e", "end of serial number range")
+	capacityCmd.Flags().StringVar(&equipCatID, "equipment-category-id", equipCatID, "equipment category ID of container numbers")
+	capacityCmd.Flags().StringVar(&excludeFile, "exclude-file", "", "list or CSV file of container numbers already in use")
+	capacityCmd.Flags().BoolVar(&excludeCheckDigit10, "exclude-check-digit-10", false, "exclude check digit 10")
+	capacityCmd.Flags().BoolVar(&excludeErrorProneSerialNumbers, "exclude-error-prone-serial-numbers", false,
+		"exclude error-prone serial numbers")
+	capacityCmd.Flags().Var(&output, configs.FlagNames.Output,
+		fmt.Sprintf("sets output to %s, %s or %s", outputCSV, outputJSON, outputNDJSON))
+	_ = capacityCmd.RegisterFlagCompletionFunc(configs.FlagNames.Output, func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{outputCSV, outputJSON, outputNDJSON}, cobra.ShellCompDirectiveNoFileComp
+	})
+	capacityCmd.Flags().Bool(configs.FlagNames.NoHeader, configs.DefaultValues.NoHeader,
+		"omits header of CSV output")
+
+	return capacityCmd
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/meyermarcel/icm/configs"
+)
+
+func Test_capacityCmd(t *testing.T) {
+	type flag struct {
+		name  string
+		value string
+	}
+	excludeFile := filepath.Join(t.TempDir(), "fleet.csv")
+	_ = os.WriteFile(excludeFile, []byte("ABCU1000000;22G1\nabc u 100001 2\nDEFU1000025\n"), 0o644)
+
+	tests := []struct {
+		name       string
+		flags      []flag
+		wantErr    bool
+		wantWriter string
+	}{
+		{
+			"Count without exclusions",
+			[]flag{{"owner", "ABC"}, {"start", "100000"}, {"end", "199999"}},
+			false,
+			`owner-code;start;end;range;used;check-digit-10;error-prone;excluded;usable
+ABC;100000;199999;100000;0;9091;7793;0;100000
+`,
+		},
+		{
+			"Count with exclusions and total",
+			[]flag{
+				{"owner", "ABC,DEF"},
+				{"start", "100000"},
+				{"end", "199999"},
+				{"exclude-file", excludeFile},
+				{"exclude-check-digit-10", "true"},
+				{"exclude-error-prone-serial-numbers", "true"},
+			},
+			false,
+			`owner-code;start;end;range;used;check-digit-10;error-prone;excluded;usable
+ABC;100000;199999;100000;2;9091;7793;12860;87140
+DEF;100000;199999;100000;1;9091;7793;12859;87141
+total;100000;199999;200000;3;18182;15586;25719;174281
+`,
+		},
+		{
+			"Count range continuing at 0 with json output",
+			[]flag{{"owner", "ABC"}, {"start", "999990"}, {"end", "9"}, {configs.FlagNames.Output, "json"}},
+			false,
+			`[
+{"owner-code":"ABC","start":999990,"end":9,"range":20,"used":0,"check-digit-10":2,"error-prone":2,"excluded":0,"usable":20}
+]
+`,
+		},
+		{
+			"Return error without owner codes",
+			nil,
+			true,
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+
+			config, _ := configs.ReadConfig(configs.DefaultConfig())
+
+			cmd := newCapacityCmd(writer, config)
+			for _, flag := range tt.flags {
+				_ = cmd.Flags().Set(flag.name, flag.value)
+			}
+			if got := cmd.RunE(cmd, nil); (got == nil) == tt.wantErr {
+				t.Errorf("got = %v, wantErr is %v", got, tt.wantErr)
+			}
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("gotWriter = %v, want %v", gotWriter, tt.wantWriter)
+			}
+		})
+	}
+}
@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/meyermarcel/icm/cont"
 	"github.com/meyermarcel/icm/data"
 	"github.com/meyermarcel/icm/http"
 	"github.com/spf13/cobra"
@@ -33,14 +40,46 @@ func (*filePathValue) Type() string {
 	return "string"
 }
 
+type importFormatValue struct {
+	value string
+}
+
+func (f *importFormatValue) String() string {
+	return f.value
+}
+
+func (f *importFormatValue) Set(value string) error {
+	switch value {
+	case http.FormatAuto, http.FormatHTML, http.FormatCSV, http.FormatJSON:
+		f.value = value
+		return nil
+	}
+	return fmt.Errorf("%s is not %s, %s, %s or %s", value, http.FormatAuto, http.FormatHTML, http.FormatCSV, http.FormatJSON)
+}
+
+func (*importFormatValue) Type() string {
+	return "string"
+}
+
+const snapshotDirName = "owner-snapshots"
+
 func newDownloadOwnersCmd(
+	stdin io.Reader,
+	writer io.Writer,
 	writeOwnersCSVFunc data.WriteOwnersCSVFunc,
+	readOwnersCSVFunc data.ReadOwnersCSVFunc,
 	timestampUpdater data.TimestampUpdater,
 	ownersDownloader http.OwnersDownloader,
 	ownerCSVPath string,
 ) (*cobra.Command, error) {
 	filePath := filePathValue{value: ownerCSVPath}
 
+	var dryRun bool
+
+	var fromFile string
+
+	format := importFormatValue{value: http.FormatAuto}
+
 	downloadOwnersCmd := &cobra.Command{
 		Aliases: []string{"update"},
 		Use:     "download-owners",
@@ -51,9 +90,28 @@ Following information is available:
   Owner code
   Company
   City
-  Country`,
+  Country
+
+Downloaded owners are compared with the owners of the file. Added, removed
+and changed owner codes are printed. Before the file is overwritten, it is
+kept as timestamped snapshot in the directory ` + snapshotDirName + ` next
+to the file. With --dry-run the changes are only printed.
+
+With --from-file owners are imported from a file instead of downloading
+them, e.g. on hosts without internet access. If the file is -, owners are
+imported from standard input. The file is a saved HTML page of the owner
+search, a CSV or a JSON export. CSV is separated by semicolons or commas and
+has the columns owner code, company, city and country. Other column orders
+need a header with these column names. JSON is an array of objects with the
+keys code or owner-code, company, city and country.`,
 		Example: `# Overwrite owner.csv file with newest owners
 icm download-owners
+# Print changes of owners without overwriting owner.csv file
+icm download-owners --dry-run
+# Import owners from a saved HTML page or an export
+icm download-owners --from-file bic-codes.html
+icm download-owners --from-file bic-codes.csv
+cat bic-codes.json | icm download-owners --from-file -
 # Create custom-owner.csv to have additional custom mapping of owner codes
 # Use semicolon as a separator. For using double quotes please see existing
 # owner.csv file.
@@ -61,7 +119,29 @@ echo 'AAA;my company;my city;my country' >> $HOME/.icm/data/custom-owner.csv`,
 		Args:              cobra.NoArgs,
 		ValidArgsFunction: cobra.NoFileCompletions,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			return overwriteOwnersFile(writeOwnersCSVFunc, timestampUpdater, ownersDownloader, filePath.value)
+			if fromFile == "" {
+				if err := timestampUpdater.Update(); err != nil {
+					return err
+				}
+				return downloadOwners(writer, writeOwnersCSVFunc, readOwnersCSVFunc, ownersDownloader,
+					filePath.value, dryRun, time.Now())
+			}
+
+			reader := stdin
+			if fromFile != "-" {
+				f, err := os.Open(fromFile)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				reader = f
+			}
+			importer, err := http.NewOwnersImporter(reader, format.value)
+			if err != nil {
+				return err
+			}
+			return downloadOwners(writer, writeOwnersCSVFunc, readOwnersCSVFunc, importer,
+				filePath.value, dryRun, time.Now())
 		},
 	}
 	downloadOwnersCmd.Flags().VarP(&filePath, "output", "o", "output file")
@@ -71,23 +151,130 @@ echo 'AAA;my company;my city;my country' >> $HOME/.icm/data/custom-owner.csv`,
 		return nil, err
 	}
 
+	downloadOwnersCmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"prints changes of owners without overwriting output file")
+	downloadOwnersCmd.Flags().StringVar(&fromFile, "from-file", "",
+		"imports owners from file or from standard input for - instead of downloading")
+	err = downloadOwnersCmd.MarkFlagFilename("from-file")
+	if err != nil {
+		return nil, err
+	}
+	downloadOwnersCmd.Flags().Var(&format, "format",
+		fmt.Sprintf("sets format of file to %s, %s, %s or %s", http.FormatAuto, http.FormatHTML, http.FormatCSV, http.FormatJSON))
+	err = downloadOwnersCmd.RegisterFlagCompletionFunc("format", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{http.FormatAuto, http.FormatHTML, http.FormatCSV, http.FormatJSON}, cobra.ShellCompDirectiveNoFileComp
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return downloadOwnersCmd, nil
 }
 
-func overwriteOwnersFile(writeOwnersCSV data.WriteOwnersCSVFunc, timestampUpdater data.TimestampUpdater, ownersDownloader http.OwnersDownloader, filePath string) error {
-	if err := timestampUpdater.Update(); err != nil {
+func downloadOwners(
+	writer io.Writer,
+	writeOwnersCSV data.WriteOwnersCSVFunc,
+	readOwnersCSV data.ReadOwnersCSVFunc,
+	ownersDownloader http.OwnersDownloader,
+	filePath string,
+	dryRun bool,
+	now time.Time,
+) error {
+	newOwners, err := ownersDownloader.Download()
+	if err != nil {
 		return err
 	}
 
-	owners, err := ownersDownloader.Download()
+	oldOwners, err := readOwnersFile(readOwnersCSV, filePath)
 	if err != nil {
 		return err
 	}
 
+	diff := cont.DiffOwners(oldOwners, newOwners)
+	if err := printOwnersDiff(writer, diff); err != nil {
+		return err
+	}
+
+	if dryRun || diff.IsEmpty() {
+		return nil
+	}
+
+	if oldOwners != nil {
+		if err := snapshotOwnersFile(filePath, now); err != nil {
+			return err
+		}
+	}
+
+	return overwriteOwnersFile(writeOwnersCSV, newOwners, filePath)
+}
+
+// readOwnersFile returns the owners of the file or nil if the file not exists.
+func readOwnersFile(readOwnersCSV data.ReadOwnersCSVFunc, filePath string) ([]cont.Owner, error) {
+	f, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	owners, err := readOwnersCSV(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	return owners, nil
+}
+
+// snapshotOwnersFile copies the file to the snapshot directory next to the file.
+// The name of the snapshot has the time now.
+func snapshotOwnersFile(filePath string, now time.Time) error {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	snapshotDir, err := initDir(filepath.Join(filepath.Dir(filePath), snapshotDirName))
+	if err != nil {
+		return err
+	}
+
+	ext := filepath.Ext(filePath)
+	name := strings.TrimSuffix(filepath.Base(filePath), ext) + "-" + now.UTC().Format("20060102T150405Z") + ext
+	return os.WriteFile(filepath.Join(snapshotDir, name), b, 0o644)
+}
+
+func overwriteOwnersFile(writeOwnersCSV data.WriteOwnersCSVFunc, owners []cont.Owner, filePath string) error {
 	file, err := os.Create(filePath)
 	if err != nil {
 		return err
 	}
 
-	return writeOwnersCSV(owners, file)
+	if err := writeOwnersCSV(owners, file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func printOwnersDiff(writer io.Writer, diff cont.OwnersDiff) error {
+	fmtOwner := func(o cont.Owner) string {
+		return strings.Join([]string{o.Code, o.Company, o.City, o.Country}, ";")
+	}
+
+	var lines []string
+	for _, o := range diff.Added {
+		lines = append(lines, "added   "+fmtOwner(o))
+	}
+	for _, o := range diff.Removed {
+		lines = append(lines, "removed "+fmtOwner(o))
+	}
+	for _, c := range diff.Changed {
+		lines = append(lines, "changed "+fmtOwner(c.Old), "     to "+fmtOwner(c.New))
+	}
+	lines = append(lines, fmt.Sprintf("%d added, %d removed, %d changed",
+		len(diff.Added), len(diff.Removed), len(diff.Changed)))
+
+	_, err := fmt.Fprintln(writer, strings.Join(lines, "\n"))
+	return err
 }
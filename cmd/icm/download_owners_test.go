@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meyermarcel/icm/cont"
+	"github.com/meyermarcel/icm/data/file"
+)
+
+type dummyOwnersDownloader []cont.Owner
+
+func (d dummyOwnersDownloader) Download() ([]cont.Owner, error) {
+	return d, nil
+}
+
+func Test_downloadOwners(t *testing.T) {
+	const oldCSV = `ABC;company;city;country
+DEF;company;city;country
+XYZ;company;city;country
+`
+	downloaded := dummyOwnersDownloader{
+		{Code: "ABC", Company: "company", City: "city", Country: "country"},
+		{Code: "DEF", Company: "other company", City: "city", Country: "country"},
+		{Code: "GHI", Company: "company", City: "city", Country: "country"},
+	}
+	const newCSV = `ABC;company;city;country
+DEF;other company;city;country
+GHI;company;city;country
+`
+	now := time.Date(2024, 5, 17, 8, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		oldCSV       string
+		downloader   dummyOwnersDownloader
+		dryRun       bool
+		wantWriter   string
+		wantCSV      string
+		wantSnapshot bool
+	}{
+		{
+			"Overwrite file and keep snapshot",
+			oldCSV,
+			downloaded,
+			false,
+			`added   GHI;company;city;country
+removed XYZ;company;city;country
+changed DEF;company;city;country
+     to DEF;other company;city;country
+1 added, 1 removed, 1 changed
+`,
+			newCSV,
+			true,
+		},
+		{
+			"Do not overwrite file with dry run",
+			oldCSV,
+			downloaded,
+			true,
+			`added   GHI;company;city;country
+removed XYZ;company;city;country
+changed DEF;company;city;country
+     to DEF;other company;city;country
+1 added, 1 removed, 1 changed
+`,
+			oldCSV,
+			false,
+		},
+		{
+			"Do not overwrite file without changes",
+			newCSV,
+			downloaded,
+			false,
+			`0 added, 0 removed, 0 changed
+`,
+			newCSV,
+			false,
+		},
+		{
+			"Write not existing file without snapshot",
+			"",
+			downloaded[:1],
+			false,
+			`added   ABC;company;city;country
+1 added, 0 removed, 0 changed
+`,
+			`ABC;company;city;country
+`,
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filePath := filepath.Join(dir, "owner.csv")
+			if tt.oldCSV != "" {
+				_ = os.WriteFile(filePath, []byte(tt.oldCSV), 0o644)
+			}
+			writer := &bytes.Buffer{}
+
+			err := downloadOwners(writer, file.WriteOwnersCSV, file.ReadOwnersCSV,
+				tt.downloader, filePath, tt.dryRun, now)
+			if err != nil {
+				t.Errorf("downloadOwners() error = %v", err)
+			}
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("gotWriter = %v, want %v", gotWriter, tt.wantWriter)
+			}
+			if gotCSV, _ := os.ReadFile(filePath); string(gotCSV) != tt.wantCSV {
+				t.Errorf("gotCSV = %v, want %v", string(gotCSV), tt.wantCSV)
+			}
+			gotSnapshot, err := os.ReadFile(filepath.Join(dir, snapshotDirName, "owner-20240517T083000Z.csv"))
+			if (err == nil) != tt.wantSnapshot {
+				t.Errorf("snapshot exists is %v, want %v", err == nil, tt.wantSnapshot)
+			}
+			if tt.wantSnapshot && string(gotSnapshot) != tt.oldCSV {
+				t.Errorf("gotSnapshot = %v, want %v", string(gotSnapshot), tt.oldCSV)
+			}
+		})
+	}
+}
+
+type failingTimestampUpdater struct{}
+
+func (failingTimestampUpdater) Update() error {
+	return errors.New("timestamp must not be updated")
+}
+
+func Test_downloadOwnersCmdFromFile(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "owner.csv")
+	_ = os.WriteFile(filePath, []byte("ABC;company;city;country\n"), 0o644)
+	stdin := strings.NewReader(`[{"owner-code":"ABC","company":"company","city":"city","country":"DE"}]`)
+	writer := &bytes.Buffer{}
+
+	cmd, err := newDownloadOwnersCmd(stdin, writer, file.WriteOwnersCSV, file.ReadOwnersCSV,
+		failingTimestampUpdater{}, nil, filePath)
+	if err != nil {
+		t.Errorf("newDownloadOwnersCmd: %v", err)
+	}
+	cmd.SetArgs([]string{"--from-file", "-"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("Execute() error = %v", err)
+	}
+	if want := "changed ABC;company;city;country\n     to ABC;company;city;Germany\n0 added, 0 removed, 1 changed\n"; writer.String() != want {
+		t.Errorf("gotWriter = %v, want %v", writer.String(), want)
+	}
+	if gotCSV, _ := os.ReadFile(filePath); string(gotCSV) != "ABC;company;city;Germany\n" {
+		t.Errorf("gotCSV = %v", string(gotCSV))
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/meyermarcel/icm/configs"
+	"github.com/meyermarcel/icm/cont"
+	"github.com/spf13/cobra"
+)
+
+const outputTable = "table"
+
+type explainOutputValue struct {
+	value string
+}
+
+func (o *explainOutputValue) String() string {
+	return o.value
+}
+
+func (o *explainOutputValue) Set(value string) error {
+	switch value {
+	case outputTable, outputJSON:
+		o.value = value
+		return nil
+	}
+	return fmt.Errorf("%s is not %s or %s", value, outputTable, outputJSON)
+}
+
+func (*explainOutputValue) Type() string {
+	return "string"
+}
+
+type explainStep struct {
+	Position  int    `json:"position"`
+	Character string `json:"character"`
+	Value     int    `json:"value"`
+	Weight    int    `json:"weight"`
+	Product   int    `json:"product"`
+}
+
+// explanation is the check digit calculation of a container number.
+type explanation struct {
+	ContNum    string        `json:"container-number"`
+	Steps      []explainStep `json:"steps"`
+	Sum        int           `json:"sum"`
+	Remainder  int           `json:"remainder"`
+	CheckDigit int           `json:"check-digit"`
+	// GivenCheckDigit and Valid are nil if the container number has no check digit.
+	GivenCheckDigit *int  `json:"given-check-digit,omitempty"`
+	Valid           *bool `json:"valid,omitempty"`
+}
+
+func newExplainCmd(writer io.Writer) *cobra.Command {
+	output := explainOutputValue{value: outputTable}
+
+	explainCmd := &cobra.Command{
+		Use:   "explain CONTAINER-NUMBER",
+		Short: "Explain the check digit calculation of a container number",
+		Long: `Explain the check digit calculation of a container number step by step
+according to ISO 6346.
+
+Every character of owner code, equipment category ID and serial number has
+a value. Digits have their value. Letters have values starting with 10 for A
+and skipping multiples of 11 (11, 22 and 33). Every value is multiplied by a
+weight of 2 to the power of the position of the character starting with 0.
+The sum of all products modulo 11 is the check digit. A remainder of 10 is
+mapped to check digit 0.
+
+The container number can have separators (e.g. ABC U 123456 0) and the check
+digit is optional. A given check digit is compared with the calculated one.
+
+The explanation is written as table or as JSON.`,
+		Example: `icm explain ABCU1234560
+icm explain 'CSQ U 305438 3'
+icm explain NYKU000000
+# Explain with JSON output
+icm explain ABCU1234560 --output json`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(_ *cobra.Command, args []string) error {
+			e, err := explain(strings.Join(args, ""))
+			if err != nil {
+				return err
+			}
+			if output.value == outputJSON {
+				encoder := json.NewEncoder(writer)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(e)
+			}
+			return printExplanation(writer, e)
+		},
+	}
+
+	explainCmd.Flags().Var(&output, configs.FlagNames.Output, fmt.Sprintf("sets output to %s or %s", outputTable, outputJSON))
+	_ = explainCmd.RegisterFlagCompletionFunc(configs.FlagNames.Output, func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{outputTable, outputJSON}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return explainCmd
+}
+
+// explain returns the explanation of the check digit calculation of a container number
+// with or without check digit. Separators between the parts are ignored.
+func explain(text string) (explanation, error) {
+	n, hasCheckDigit, err := cont.ParseNumberOptionalCheckDigit(text)
+	if err != nil {
+		return explanation{}, err
+	}
+
+	c := cont.ExplainCheckDigit(n.OwnerCode, n.EquipCatID, n.SerialNumber)
+	e := explanation{
+		ContNum:    fmt.Sprintf("%s%c%06d", n.OwnerCode, n.EquipCatID, n.SerialNumber),
+		Sum:        c.Sum,
+		Remainder:  c.Remainder,
+		CheckDigit: c.CheckDigit,
+	}
+	for i, step := range c.Steps {
+		e.Steps = append(e.Steps, explainStep{i + 1, string(step.Char), step.Value, step.Weight, step.Product})
+	}
+	if hasCheckDigit {
+		e.ContNum += fmt.Sprint(n.CheckDigit)
+		valid := n.CheckDigit == c.CheckDigit
+		e.GivenCheckDigit = &n.CheckDigit
+		e.Valid = &valid
+	}
+	return e, nil
+}
+
+func printExplanation(writer io.Writer, e explanation) error {
+	w := tabwriter.NewWriter(writer, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "position\tcharacter\tvalue\tweight\tproduct\t\n")
+	for _, step := range e.Steps {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t\n", step.Position, step.Character, step.Value, step.Weight, step.Product)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	mapping := ""
+	if e.Remainder == 10 {
+		mapping = " (remainder 10 is mapped to 0)"
+	}
+	lines := []string{
+		"",
+		fmt.Sprintf("  sum of products = %d", e.Sum),
+		fmt.Sprintf("%17s = %d", fmt.Sprintf("%d mod 11", e.Sum), e.Remainder),
+		fmt.Sprintf("      check digit = %d%s", e.CheckDigit, mapping),
+	}
+	if e.GivenCheckDigit != nil {
+		validity := "valid"
+		if !*e.Valid {
+			validity = "invalid"
+		}
+		lines = append(lines, fmt.Sprintf("given check digit = %d (%s)", *e.GivenCheckDigit, validity))
+	}
+	_, err := io.WriteString(writer, strings.Join(lines, "\n")+"\n")
+	return err
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_explainCmd(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		output     string
+		wantErr    bool
+		wantWriter string
+	}{
+		{
+			"Explain valid container number",
+			[]string{"CSQ", "U", "305438", "3"},
+			outputTable,
+			false,
+			`  position  character  value  weight  product
+         1          C     13       1       13
+         2          S     30       2       60
+         3          Q     28       4      112
+         4          U     32       8      256
+         5          3      3      16       48
+         6          0      0      32        0
+         7          5      5      64      320
+         8          4      4     128      512
+         9          3      3     256      768
+        10          8      8     512     4096
+
+  sum of products = 6185
+      6185 mod 11 = 3
+      check digit = 3
+given check digit = 3 (valid)
+`,
+		},
+		{
+			"Explain container number without check digit and remainder 10",
+			[]string{"nyku000000"},
+			outputTable,
+			false,
+			`  position  character  value  weight  product
+         1          N     25       1       25
+         2          Y     37       2       74
+         3          K     21       4       84
+         4          U     32       8      256
+         5          0      0      16        0
+         6          0      0      32        0
+         7          0      0      64        0
+         8          0      0     128        0
+         9          0      0     256        0
+        10          0      0     512        0
+
+  sum of products = 439
+       439 mod 11 = 10
+      check digit = 0 (remainder 10 is mapped to 0)
+`,
+		},
+		{
+			"Explain invalid container number with json output",
+			[]string{"NYK U 000000-1"},
+			outputJSON,
+			false,
+			`{
+  "container-number": "NYKU0000001",
+  "steps": [
+    {
+      "position": 1,
+      "character": "N",
+      "value": 25,
+      "weight": 1,
+      "product": 25
+    },
+    {
+      "position": 2,
+      "character": "Y",
+      "value": 37,
+      "weight": 2,
+      "product": 74
+    },
+    {
+      "position": 3,
+      "character": "K",
+      "value": 21,
+      "weight": 4,
+      "product": 84
+    },
+    {
+      "position": 4,
+      "character": "U",
+      "value": 32,
+      "weight": 8,
+      "product": 256
+    },
+    {
+      "position": 5,
+      "character": "0",
+      "value": 0,
+      "weight": 16,
+      "product": 0
+    },
+    {
+      "position": 6,
+      "character": "0",
+      "value": 0,
+      "weight": 32,
+      "product": 0
+    },
+    {
+      "position": 7,
+      "character": "0",
+      "value": 0,
+      "weight": 64,
+      "product": 0
+    },
+    {
+      "position": 8,
+      "character": "0",
+      "value": 0,
+      "weight": 128,
+      "product": 0
+    },
+    {
+      "position": 9,
+      "character": "0",
+      "value": 0,
+      "weight": 256,
+      "product": 0
+    },
+    {
+      "position": 10,
+      "character": "0",
+      "value": 0,
+      "weight": 512,
+      "product": 0
+    }
+  ],
+  "sum": 439,
+  "remainder": 10,
+  "check-digit": 0,
+  "given-check-digit": 1,
+  "valid": false
+}
+`,
+		},
+		{
+			"Return error for too short container number",
+			[]string{"ABCU12345"},
+			outputTable,
+			true,
+			"",
+		},
+		{
+			"Return error for owner code",
+			[]string{"AB1U1234560"},
+			outputTable,
+			true,
+			"",
+		},
+		{
+			"Return error for serial number",
+			[]string{"ABCU12A4560"},
+			outputTable,
+			true,
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+
+			cmd := newExplainCmd(writer)
+			_ = cmd.Flags().Set("output", tt.output)
+			if got := cmd.RunE(cmd, tt.args); (got == nil) == tt.wantErr {
+				t.Errorf("got = %v, wantErr is %v", got, tt.wantErr)
+			}
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("gotWriter = %v, want %v", gotWriter, tt.wantWriter)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/meyermarcel/icm/configs"
+	"github.com/meyermarcel/icm/cont"
+	"github.com/spf13/cobra"
+)
+
+const maxLineBytes = 1 << 20
+
+type extractedNumber struct {
+	File           string `json:"file"`
+	Line           int    `json:"line"`
+	Column         int    `json:"column"`
+	Text           string `json:"text"`
+	ContNum        string `json:"container-number"`
+	CheckDigit     int    `json:"check-digit"`
+	CalcCheckDigit int    `json:"calculated-check-digit"`
+	Valid          bool   `json:"valid-check-digit"`
+}
+
+var extractedNumberHeader = []string{
+	"file", "line", "column", "text", "container-number",
+	"check-digit", "calculated-check-digit", "valid-check-digit",
+}
+
+func (e extractedNumber) record() []string {
+	return []string{
+		e.File,
+		strconv.Itoa(e.Line),
+		strconv.Itoa(e.Column),
+		e.Text,
+		e.ContNum,
+		strconv.Itoa(e.CheckDigit),
+		strconv.Itoa(e.CalcCheckDigit),
+		strconv.FormatBool(e.Valid),
+	}
+}
+
+func newExtractCmd(stdin io.Reader, writer io.Writer, config *configs.Config) *cobra.Command {
+	output := recordOutputValue{value: outputCSV}
+
+	extractCmd := &cobra.Command{
+		Use:   "extract [FILE]...",
+		Short: "Extract container numbers from arbitrary text",
+		Long: `Extract container numbers from arbitrary text like bills of lading,
+emails or manifests.
+
+Every file is scanned for container numbers with upper case letters. If no
+file or - is specified, standard input is scanned. Between owner code,
+equipment category ID, serial number and check digit the configured
+separators or no separator are tolerated.
+
+For every found container number the file, the line and the column (both
+starting with 1), the text as found and the validity of the check digit
+are written as CSV, JSON or newline delimited JSON.
+
+` + sepHelp,
+		Example: `icm extract bill-of-lading.txt
+cat email.txt | icm extract
+# Extract with JSON output
+icm extract --output json manifest.txt
+# Tolerate a dash between serial number and check digit
+icm extract --sep-serial-check '-' manifest.txt
+# Validate all extracted container numbers
+icm extract --no-header manifest.txt | cut -d ';' -f 5 | icm validate`,
+		ValidArgsFunction: func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveDefault
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config.Overwrite(cmd.Flags())
+
+			extractor := cont.NewExtractor(config.SepOE(), config.SepES(), config.SepSC())
+
+			if len(args) == 0 {
+				args = []string{"-"}
+			}
+
+			printer := newRecordPrinter(writer, output.value, config.NoHeader(), extractedNumberHeader)
+			for _, name := range args {
+				if err := extractFile(extractor, printer, name, stdin); err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+			}
+			return printer.close()
+		},
+	}
+
+	extractCmd.Flags().SortFlags = false
+
+	extractCmd.Flags().Var(&output, configs.FlagNames.Output,
+		fmt.Sprintf("sets output to %s, %s or %s", outputCSV, outputJSON, outputNDJSON))
+	_ = extractCmd.RegisterFlagCompletionFunc(configs.FlagNames.Output, func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{outputCSV, outputJSON, outputNDJSON}, cobra.ShellCompDirectiveNoFileComp
+	})
+	extractCmd.Flags().Bool(configs.FlagNames.NoHeader, configs.DefaultValues.NoHeader,
+		"omits header of CSV output")
+	extractCmd.Flags().String(configs.FlagNames.SepOE, configs.DefaultValues.SepOE,
+		"ABC(x)U1234560  (x) separates owner code and equipment category id")
+	extractCmd.Flags().String(configs.FlagNames.SepES, configs.DefaultValues.SepES,
+		"ABCU(x)1234560  (x) separates equipment category id and serial number")
+	extractCmd.Flags().String(configs.FlagNames.SepSC, configs.DefaultValues.SepSC,
+		"ABCU123456(x)0  (x) separates serial number and check digit")
+
+	return extractCmd
+}
+
+func extractFile(extractor *cont.Extractor, printer *recordPrinter, name string, stdin io.Reader) error {
+	reader := stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineBytes)
+	line := 0
+	for scanner.Scan() {
+		line++
+		for _, e := range extractor.Extract(scanner.Text()) {
+			err := printer.print(extractedNumber{
+				File:           name,
+				Line:           line,
+				Column:         e.Column,
+				Text:           e.Text,
+				ContNum:        e.Number.String(),
+				CheckDigit:     e.Number.CheckDigit,
+				CalcCheckDigit: e.CalcCheckDigit,
+				Valid:          e.Valid(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/meyermarcel/icm/configs"
+)
+
+func Test_extractCmd(t *testing.T) {
+	type flag struct {
+		name  string
+		value string
+	}
+	const text = `Dear team,
+please load ABCU 123456 0 and XYZU1234561.
+Booking for RAN U 000003-5 confirmed.
+`
+	tests := []struct {
+		name       string
+		flags      []flag
+		stdin      string
+		wantErr    bool
+		wantWriter string
+	}{
+		{
+			"Extract with csv output",
+			nil,
+			text,
+			false,
+			`file;line;column;text;container-number;check-digit;calculated-check-digit;valid-check-digit
+-;2;13;ABCU 123456 0;ABCU1234560;0;0;true
+-;2;31;XYZU1234561;XYZU1234561;1;0;false
+`,
+		},
+		{
+			"Extract with custom separator and without header",
+			[]flag{
+				{configs.FlagNames.SepSC, "-"},
+				{configs.FlagNames.NoHeader, "true"},
+			},
+			text,
+			false,
+			`-;2;13;ABCU 123456 0;ABCU1234560;0;0;true
+-;2;31;XYZU1234561;XYZU1234561;1;0;false
+-;3;13;RAN U 000003-5;RANU0000035;5;5;true
+`,
+		},
+		{
+			"Extract with json output",
+			[]flag{{configs.FlagNames.Output, "json"}},
+			text,
+			false,
+			`[
+{"file":"-","line":2,"column":13,"text":"ABCU 123456 0","container-number":"ABCU1234560","check-digit":0,"calculated-check-digit":0,"valid-check-digit":true},
+{"file":"-","line":2,"column":31,"text":"XYZU1234561","container-number":"XYZU1234561","check-digit":1,"calculated-check-digit":0,"valid-check-digit":false}
+]
+`,
+		},
+		{
+			"Extract nothing with json output",
+			[]flag{{configs.FlagNames.Output, "json"}},
+			"nothing",
+			false,
+			`[]
+`,
+		},
+		{
+			"Extract with ndjson output",
+			[]flag{{configs.FlagNames.Output, "ndjson"}},
+			text,
+			false,
+			`{"file":"-","line":2,"column":13,"text":"ABCU 123456 0","container-number":"ABCU1234560","check-digit":0,"calculated-check-digit":0,"valid-check-digit":true}
+{"file":"-","line":2,"column":31,"text":"XYZU1234561","container-number":"XYZU1234561","check-digit":1,"calculated-check-digit":0,"valid-check-digit":false}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+
+			config, _ := configs.ReadConfig(configs.DefaultConfig())
+
+			cmd := newExtractCmd(strings.NewReader(tt.stdin), writer, config)
+			for _, flag := range tt.flags {
+				_ = cmd.Flags().Set(flag.name, flag.value)
+			}
+			if got := cmd.RunE(cmd, nil); (got == nil) == tt.wantErr {
+				t.Errorf("got = %v, wantErr is %v", got, tt.wantErr)
+			}
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("gotWriter = %v, want %v", gotWriter, tt.wantWriter)
+			}
+		})
+	}
+}
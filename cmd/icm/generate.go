@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"math/rand/v2"
-	"path/filepath"
+	"os"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
+	"text/template"
 
 	"github.com/meyermarcel/icm/configs"
 	"github.com/meyermarcel/icm/cont"
@@ -38,24 +46,50 @@ func (*countValue) Type() string {
 	return "int"
 }
 
-type ownerValue struct {
-	value string
+type ownersValue struct {
+	values []string
 }
 
-func (o *ownerValue) String() string {
-	return o.value
+func (o *ownersValue) String() string {
+	return strings.Join(o.values, ",")
 }
 
-func (o *ownerValue) Set(value string) error {
-	if err := cont.IsOwnerCode(value); err != nil {
+func (o *ownersValue) Set(value string) error {
+	for _, code := range strings.Split(value, ",") {
+		if err := cont.IsOwnerCode(code); err != nil {
+			return fmt.Errorf("%s: %w", code, err)
+		}
+		o.values = append(o.values, code)
+	}
+	return nil
+}
+
+func (*ownersValue) Type() string {
+	return "strings"
+}
+
+type regexpValue struct {
+	value *regexp.Regexp
+}
+
+func (r *regexpValue) String() string {
+	if r.value == nil {
+		return ""
+	}
+	return r.value.String()
+}
+
+func (r *regexpValue) Set(value string) error {
+	re, err := regexp.Compile(value)
+	if err != nil {
 		return err
 	}
-	o.value = value
+	r.value = re
 	return nil
 }
 
-func (*ownerValue) Type() string {
-	return "string"
+func (*regexpValue) Type() string {
+	return "regexp"
 }
 
 type serialNumValue struct {
@@ -82,30 +116,202 @@ func (*serialNumValue) Type() string {
 	return "int"
 }
 
-func newGenerateCmd(writer, writerErr io.Writer, config *configs.Config, ownerDecoder data.OwnerDecoder, r *rand.Rand) *cobra.Command {
+// generatedNumber is a generated container number for templates and records.
+type generatedNumber struct {
+	ContNum      string `json:"container-number"`
+	OwnerCode    string `json:"owner-code"`
+	EquipCatID   string `json:"equipment-category-id"`
+	SerialNumber string `json:"serial-number"`
+	CheckDigit   int    `json:"check-digit"`
+	// SizeType is empty if no size and type codes are generated.
+	SizeType string `json:"size-type"`
+	Size     string `json:"-"`
+	Type     string `json:"-"`
+	// ErrorKind is the kind of the injected error or empty for valid container numbers.
+	ErrorKind string `json:"-"`
+}
+
+var generatedNumberHeader = []string{
+	"container-number", "owner-code", "equipment-category-id", "serial-number", "check-digit", "size-type",
+}
+
+func (g generatedNumber) record() []string {
+	return []string{g.ContNum, g.OwnerCode, g.EquipCatID, g.SerialNumber, strconv.Itoa(g.CheckDigit), g.SizeType}
+}
+
+func newGeneratedNumber(cn cont.Number, sizeType string) generatedNumber {
+	g := generatedNumber{
+		ContNum:      fmt.Sprintf("%s%c%06d%d", cn.OwnerCode, cn.EquipCatID, cn.SerialNumber, cn.CheckDigit),
+		OwnerCode:    cn.OwnerCode,
+		EquipCatID:   string(cn.EquipCatID),
+		SerialNumber: fmt.Sprintf("%06d", cn.SerialNumber),
+		CheckDigit:   cn.CheckDigit,
+		SizeType:     sizeType,
+	}
+	if sizeType != "" {
+		g.Size = sizeType[0:2]
+		g.Type = sizeType[2:4]
+	}
+	return g
+}
+
+// formatValue is csv, json, ndjson or a template. An empty format uses the separators.
+type formatValue struct {
+	value    string
+	template *template.Template
+}
+
+func (f *formatValue) String() string {
+	return f.value
+}
+
+func (f *formatValue) Set(value string) error {
+	f.template = nil
+	switch value {
+	case "", outputCSV, outputJSON, outputNDJSON:
+	default:
+		tmpl, err := template.New("format").Option("missingkey=error").Parse(value)
+		if err != nil {
+			return err
+		}
+		// Unknown fields are found before generation.
+		if err := tmpl.Execute(io.Discard, generatedNumber{}); err != nil {
+			return err
+		}
+		f.template = tmpl
+	}
+	f.value = value
+	return nil
+}
+
+func (*formatValue) Type() string {
+	return "string"
+}
+
+func newGenerateCmd(
+	writer, writerErr io.Writer,
+	config *configs.Config,
+	decoders decoders,
+	ownerCreator data.WriteOwnersCSVFunc,
+	r *rand.Rand,
+) *cobra.Command {
 	count := countValue{value: 1}
 	startValue := serialNumValue{}
 	endValue := serialNumValue{}
-	ownerValue := ownerValue{}
+	owners := ownersValue{}
+	var ownerFile string
+	fictitiousOwners := countValue{}
+	var fictitiousOwnersFile string
+	var countries []string
+	companyRegex := regexpValue{}
+	equipCatID := "U"
+	var sizeType string
+	var sizeTypeFile string
+	var seed uint64
+	var stateFile string
+	format := formatValue{}
+	invalid := invalidKindsValue{}
+	invalidRatio := ratioValue{value: 1}
+	var excludeFile string
 	var excludeCheckDigit10 bool
 	var excludeErrorProneSerialNumbers bool
 
 	generateCmd := &cobra.Command{
 		Use:   "generate",
 		Short: "Generate unique container numbers",
-		Long: `Generated container numbers are unique. Owners specified in
+		Long: `Generated container numbers are unique. Owners of the owner sources
+configured in
 
-  ` + filepath.Join("$HOME", appDir, "data", "owner.csv") + `
+  ` + ownerSourcesPath + `
 
-are used. Owners can be updated by 'icm download-owners --help' command.
+are used except blocked owner codes. Owners can be updated by
+'icm download-owners --help' command.
 
 Equipment category ID 'U' is used for every generated container number.
-
-For a custom owner code use the --owner-code flag.
+For other equipment categories (e.g. J or Z) use the --equipment-category-id
+flag.
+
+With the --size-type flag every container number has a size and type code.
+With value '` + sizeTypeRandom + `' the length, the height and width and the type code are
+picked at random of all known codes. Otherwise, the value is a size and type
+code (e.g. 22G1) used for every container number. With the --size-type-file
+flag size and type codes are picked with a distribution file. Every line of
+the file has a size and type code and optionally a weight separated by
+semicolon or comma (e.g. 22G1;60). Codes with higher weights are picked more
+often. Lines starting with # are ignored.
+
+For custom owner codes use the --owner flag or the --owner-file flag with
+a file of owner codes. Owner codes of the file are separated by new lines.
+Further fields of a line separated by semicolon or comma and lines starting
+with # are ignored, so owner.csv files can be used.
+
+Owners can be filtered by country with the --country flag and by company with
+the --company-regex flag. Countries are names (e.g. Germany) or ISO 3166-1
+alpha-2 codes (e.g. DE). Filters are resolved against the owners and apply
+to the custom owner codes if there are some.
+
+For synthetic data without owner codes of real owners use the
+--fictitious-owners flag with the count of owner codes. Fictitious owner codes
+are picked at random of all owner codes that are neither registered in the
+owner sources nor blocked. With the --fictitious-owners-file flag the
+fictitious owners are written to a file in the format of owner.csv, so the
+file can be added as owner source to validate the generated container numbers.
 
 For a custom serial number use the --start and --end flags and optionally the --count flag.
+Without --count all available container numbers from --start to --end are generated.
 Using only the --count flag generates pseudo random serial numbers.
 
+With the --state flag generation continues with the state of the previous
+generation in the state file. After generation the state file is updated or
+created if it does not exist. Container numbers are unique across all
+generations with the same state file. Owners, equipment category ID and size
+and type codes must be the same for all generations and --start and --end can
+only be used for the first generation. The state file continues the sequence
+of random size and type codes, so generations with --seed and a state file
+result in the same size and type codes as a single generation.
+
+With the --exclude-file flag container numbers already in use are not
+generated. The file is a list or CSV file of container numbers. Container
+numbers with the same owner code, equipment category ID and serial number
+as a container number of the file are skipped. Excluded container numbers of a serial number range
+are not replaced by container numbers outside of the range, so generation
+fails if the range has fewer available container numbers than requested.
+
+With the --format flag container numbers are written as CSV, JSON, newline
+delimited JSON or with a Go template (see pkg.go.dev/text/template). The
+template is executed for every container number followed by a new line.
+Fields of a template are
+
+  .ContNum       container number without separators (e.g. ABCU1234560)
+  .OwnerCode     owner code (e.g. ABC)
+  .EquipCatID    equipment category ID (e.g. U)
+  .SerialNumber  serial number with leading zeros (e.g. 123456)
+  .CheckDigit    check digit (e.g. 0)
+  .SizeType      size and type code (e.g. 22G1), empty without size and type
+  .Size          size code (e.g. 22), empty without size and type
+  .Type          type code (e.g. G1), empty without size and type
+
+Separator flags are only used without the --format flag.
+
+With the --invalid flag errors are injected into container numbers for
+negative test data. Kinds of errors are
+
+  ` + strings.Join(invalidKinds, "\n  ") + `
+
+or ` + invalidAll + ` for all kinds. For every invalid container number a kind is picked
+at random. The --invalid-ratio flag sets the ratio of invalid container
+numbers with up to 6 decimal places (e.g. 0.1 for 1 invalid in 10 container
+numbers). The kind of the injected error is written separated by semicolon,
+as error-kind column or as .ErrorKind field of a template and is empty for
+valid container numbers. Error kinds that cannot be injected into a container
+number are replaced by ` + invalidCheckDigit + `. Container numbers of ` + invalidLowerCase + ` are
+only invalid for consumers that require upper case letters, 'icm validate'
+accepts them.
+
+With the --seed flag pseudo random generation is reproducible. The same seed
+and the same flags, owners and size and type codes result in the same
+container numbers on every run and platform.
+
 ` + sepHelp,
 		Example: `icm generate
 icm generate --count 10
@@ -117,6 +323,32 @@ icm generate --count 10 --exclude-check-digit-10 --exclude-error-prone-serial-nu
 icm generate --start 100500 --count 10
 icm generate --start 100500 --end 100600
 icm generate --start 100500 --end 100600 --owner ABC
+# Generate container numbers of specific owners
+icm generate --count 10 --owner ABC,DEF --owner GHI
+icm generate --count 10 --owner-file owners.txt
+# Generate container numbers of 3 owners that are not registered
+icm generate --count 10 --fictitious-owners 3 --fictitious-owners-file fictitious-owner.csv
+# Generate container numbers of owners filtered by country and company
+icm generate --count 10 --country DE --company-regex 'Line'
+# Generate 100 container numbers today and 100 other ones tomorrow
+icm generate --count 100 --start 100000 --owner ABC --state block.json
+icm generate --count 100 --owner ABC --state block.json
+# Generate container numbers not already in use
+icm generate --count 10 --owner ABC --exclude-file fleet.csv
+# Generate the same container numbers on every run
+icm generate --count 10 --seed 42
+# Generate container numbers for label printers and other systems
+icm generate --count 10 --format '{{.OwnerCode}}{{.EquipCatID}} {{.SerialNumber}}-{{.CheckDigit}}'
+icm generate --count 10 --format '{{printf "%-12s" .ContNum}}{{.SizeType}}' --size-type random
+icm generate --count 10 --format csv
+icm generate --count 10 --format json
+# Generate negative test data with 1 invalid in 10 container numbers
+icm generate --count 100 --invalid all --invalid-ratio 0.1
+icm generate --count 10 --invalid check-digit,transposition --format csv
+# Generate complete markings
+icm generate --count 10 --size-type 22G1
+icm generate --count 10 --size-type random --equipment-category-id Z
+icm generate --count 10 --size-type-file size-types.csv
 # Generate CSV data set
 icm generate --count 1000000 | icm validate`,
 		Args:              cobra.NoArgs,
@@ -124,15 +356,64 @@ icm generate --count 1000000 | icm validate`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			config.Overwrite(cmd.Flags())
 
+			if cmd.Flags().Changed("seed") {
+				r = rand.New(rand.NewPCG(seed, 0))
+			}
+
 			builder := cont.NewUniqueGeneratorBuilder(r).
-				Count(count.value).
 				ExcludeCheckDigit10(excludeCheckDigit10).
 				ExcludeErrorProneSerialNumbers(excludeErrorProneSerialNumbers)
 
-			if cmd.Flags().Changed("owner") {
-				builder.OwnerCodes([]string{ownerValue.value})
+			var ownerCodes []string
+			if cmd.Flags().Changed("fictitious-owners") {
+				fictitious, err := newFictitiousOwners(decoders.ownerDecodeUpdater, fictitiousOwners.value, r)
+				if err != nil {
+					return err
+				}
+				if fictitiousOwnersFile != "" {
+					if err := writeFictitiousOwnersFile(ownerCreator, fictitious, fictitiousOwnersFile); err != nil {
+						return err
+					}
+				}
+				for _, owner := range fictitious {
+					ownerCodes = append(ownerCodes, owner.Code)
+				}
 			} else {
-				builder.OwnerCodes(ownerDecoder.GetAllOwnerCodes())
+				if fictitiousOwnersFile != "" {
+					return errors.New("--fictitious-owners-file requires --fictitious-owners")
+				}
+				codes := owners.values
+				if ownerFile != "" {
+					fileCodes, err := readOwnerCodesFile(ownerFile)
+					if err != nil {
+						return err
+					}
+					codes = append(codes, fileCodes...)
+				}
+				var err error
+				ownerCodes, err = resolveOwnerCodes(decoders.ownerDecodeUpdater, codes, countries, companyRegex.value)
+				if err != nil {
+					return err
+				}
+			}
+			builder.OwnerCodes(ownerCodes)
+
+			if err := cont.IsEquipCatID(equipCatID); err != nil {
+				return err
+			}
+			if found, _ := decoders.equipCatDecoder.Decode(equipCatID); !found {
+				return fmt.Errorf("%s is not a known equipment category ID", equipCatID)
+			}
+			builder.EquipCatID(rune(equipCatID[0]))
+
+			sizeTypes, weights, err := sizeTypeCodes(decoders.sizeTypeDecoders, sizeType, sizeTypeFile)
+			if err != nil {
+				return err
+			}
+			builder.SizeTypeCodes(sizeTypes, weights)
+
+			if cmd.Flags().Changed("count") {
+				builder.Count(count.value)
 			}
 
 			if cmd.Flags().Changed("start") {
@@ -143,18 +424,54 @@ icm generate --count 1000000 | icm validate`,
 				builder.End(endValue.value)
 			}
 
+			if excludeFile != "" {
+				excluded, err := readExcludeFile(excludeFile, config)
+				if err != nil {
+					return err
+				}
+				builder.Exclude(excluded)
+			}
+
+			if stateFile != "" {
+				state, err := readGeneratorState(stateFile)
+				if err != nil {
+					return err
+				}
+				if state != nil {
+					builder.State(state)
+				}
+			}
+
 			generator, err := builder.Build()
 			if err != nil {
 				return err
 			}
-			for generator.Generate() {
-				cn := generator.ContNum()
-				_, err := io.WriteString(writer, fmt.Sprintf("%s%s%s%s%06d%s%d\n",
-					cn.OwnerCode, config.SepOE(),
-					string(cn.EquipCatID), config.SepES(),
-					cn.SerialNumber, config.SepSC(),
-					cn.CheckDigit))
-				writeErr(writerErr, err)
+			var injector *invalidInjector
+			if len(invalid.values) > 0 {
+				injector = &invalidInjector{
+					rand:            r,
+					kinds:           invalid.values,
+					ratio:           invalidRatio.scaled(),
+					ownerDecoder:    decoders.ownerDecodeUpdater,
+					equipCatDecoder: decoders.equipCatDecoder,
+				}
+			}
+			numbers := func(yield func(generatedNumber) bool) {
+				for generator.Generate() {
+					g := newGeneratedNumber(generator.ContNum(), generator.SizeTypeCode())
+					if injector != nil {
+						g = injector.next(g)
+					}
+					if !yield(g) {
+						return
+					}
+				}
+			}
+			if err := printGenerated(writer, writerErr, numbers, format, injector != nil, config); err != nil {
+				return err
+			}
+			if stateFile != "" {
+				return writeGeneratorState(stateFile, generator.State())
 			}
 			return nil
 		},
@@ -165,7 +482,30 @@ icm generate --count 1000000 | icm validate`,
 	generateCmd.Flags().VarP(&count, "count", "c", "count of container numbers")
 	generateCmd.Flags().VarP(&startValue, "start", "s", "start of serial number range")
 	generateCmd.Flags().VarP(&endValue, "end", "e", "end of serial number range")
-	generateCmd.Flags().Var(&ownerValue, "owner", "custom owner code")
+	generateCmd.Flags().StringVar(&stateFile, "state", "", "state file to continue generation and to update after generation")
+	generateCmd.Flags().Uint64Var(&seed, "seed", 0, "seed for reproducible pseudo random generation")
+	generateCmd.Flags().Var(&owners, "owner", "custom owner codes, repeated or separated by comma")
+	generateCmd.Flags().StringVar(&ownerFile, "owner-file", "", "file with custom owner codes")
+	generateCmd.Flags().Var(&fictitiousOwners, "fictitious-owners", "count of fictitious owner codes that are not registered")
+	generateCmd.Flags().StringVar(&fictitiousOwnersFile, "fictitious-owners-file", "", "file to write fictitious owners to")
+	generateCmd.Flags().StringSliceVar(&countries, "country", nil, "uses only owners of countries, repeated or separated by comma")
+	_ = generateCmd.RegisterFlagCompletionFunc("country", cobra.NoFileCompletions)
+	generateCmd.Flags().Var(&companyRegex, "company-regex", "uses only owners with a company matching the regular expression")
+	_ = generateCmd.RegisterFlagCompletionFunc("company-regex", cobra.NoFileCompletions)
+	generateCmd.Flags().StringVar(&equipCatID, "equipment-category-id", equipCatID, "equipment category ID of container numbers")
+	_ = generateCmd.RegisterFlagCompletionFunc("equipment-category-id", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return slices.Sorted(slices.Values(decoders.equipCatDecoder.AllCatIDs())), cobra.ShellCompDirectiveNoFileComp
+	})
+	generateCmd.Flags().StringVar(&sizeType, "size-type", "", "size and type code (e.g. 22G1) or "+sizeTypeRandom+" for random size and type codes")
+	_ = generateCmd.RegisterFlagCompletionFunc("size-type", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{sizeTypeRandom}, cobra.ShellCompDirectiveNoFileComp
+	})
+	generateCmd.Flags().StringVar(&sizeTypeFile, "size-type-file", "", "distribution file of size and type codes with weights")
+	generateCmd.MarkFlagsMutuallyExclusive("size-type", "size-type-file")
+	for _, flag := range []string{"owner", "owner-file", "country", "company-regex"} {
+		generateCmd.MarkFlagsMutuallyExclusive("fictitious-owners", flag)
+	}
+	generateCmd.Flags().StringVar(&excludeFile, "exclude-file", "", "list or CSV file of container numbers already in use")
 	generateCmd.Flags().BoolVar(&excludeCheckDigit10, "exclude-check-digit-10", false, "exclude check digit 10")
 	generateCmd.Flags().BoolVar(&excludeErrorProneSerialNumbers, "exclude-transposition-errors", false,
 		"exclude possible transposition errors")
@@ -176,12 +516,368 @@ icm generate --count 1000000 | icm validate`,
 	generateCmd.Flags().BoolVar(&excludeErrorProneSerialNumbers, "exclude-error-prone-serial-numbers", false,
 		"exclude error-prone serial numbers. For example swapping the second 0 and first 1 of RCB U 001130 0 results in container number RCB U 010130 0 with a valid check digit 0")
 
+	generateCmd.Flags().Var(&invalid, "invalid",
+		fmt.Sprintf("injects errors of kinds (%s or %s) into container numbers", strings.Join(invalidKinds, ", "), invalidAll))
+	_ = generateCmd.RegisterFlagCompletionFunc("invalid", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return append([]string{invalidAll}, invalidKinds...), cobra.ShellCompDirectiveNoFileComp
+	})
+	generateCmd.Flags().Var(&invalidRatio, "invalid-ratio", "ratio of invalid container numbers with --invalid")
+	generateCmd.Flags().Var(&format, "format",
+		fmt.Sprintf("sets format to %s, %s, %s or a template", outputCSV, outputJSON, outputNDJSON))
+	_ = generateCmd.RegisterFlagCompletionFunc("format", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{outputCSV, outputJSON, outputNDJSON}, cobra.ShellCompDirectiveNoFileComp
+	})
+	generateCmd.Flags().Bool(configs.FlagNames.NoHeader, configs.DefaultValues.NoHeader,
+		"omits header of CSV format")
 	generateCmd.Flags().String(configs.FlagNames.SepOE, configs.DefaultValues.SepOE,
 		"ABC(x)U1234560  (x) separates owner code and equipment category id")
 	generateCmd.Flags().String(configs.FlagNames.SepES, configs.DefaultValues.SepES,
 		"ABCU(x)1234560  (x) separates equipment category id and serial number")
 	generateCmd.Flags().String(configs.FlagNames.SepSC, configs.DefaultValues.SepSC,
 		"ABCU123456(x)0  (x) separates serial number and check digit")
+	generateCmd.Flags().String(configs.FlagNames.SepCS, configs.DefaultValues.SepCS,
+		"ABCU1234560(x)20G1  (x) separates check digit and size")
+	generateCmd.Flags().String(configs.FlagNames.SepST, configs.DefaultValues.SepST,
+		"ABCU1234560 20(x)G1  (x) separates size and type")
 
 	return generateCmd
 }
+
+// printGenerated writes all generated container numbers of numbers in format.
+// With withErrorKind the kind of the injected error is written for every container number.
+func printGenerated(writer, writerErr io.Writer, numbers iter.Seq[generatedNumber], format formatValue,
+	withErrorKind bool, config *configs.Config,
+) error {
+	switch {
+	case format.template != nil:
+		for g := range numbers {
+			if err := format.template.Execute(writer, g); err != nil {
+				return err
+			}
+			_, err := io.WriteString(writer, "\n")
+			writeErr(writerErr, err)
+		}
+	case format.value != "":
+		if withErrorKind {
+			printer := newRecordPrinter(writer, format.value, config.NoHeader(), invalidRecordHeader)
+			for g := range numbers {
+				if err := printer.print(invalidRecord{g, g.ErrorKind}); err != nil {
+					return err
+				}
+			}
+			return printer.close()
+		}
+		printer := newRecordPrinter(writer, format.value, config.NoHeader(), generatedNumberHeader)
+		for g := range numbers {
+			if err := printer.print(g); err != nil {
+				return err
+			}
+		}
+		return printer.close()
+	default:
+		for g := range numbers {
+			errorKind := ""
+			if withErrorKind {
+				errorKind = ";" + g.ErrorKind
+			}
+			_, err := io.WriteString(writer, g.OwnerCode+config.SepOE()+
+				g.EquipCatID+config.SepES()+
+				g.SerialNumber+config.SepSC()+
+				strconv.Itoa(g.CheckDigit)+
+				formatSizeTypeCode(g.SizeType, config)+
+				errorKind+"\n")
+			writeErr(writerErr, err)
+		}
+	}
+	return nil
+}
+
+// readOwnerCodesFile returns the owner codes of the first field of every line
+// of the file at path. Empty lines and lines starting with # are ignored.
+func readOwnerCodesFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var codes []string
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		code, _, _ := strings.Cut(strings.ReplaceAll(text, ",", ";"), ";")
+		code = strings.TrimSpace(code)
+		if err := cont.IsOwnerCode(code); err != nil {
+			return nil, fmt.Errorf("%s: line %d: %s: %w", path, line, code, err)
+		}
+		codes = append(codes, code)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("%s: no owner codes found", path)
+	}
+	return codes, nil
+}
+
+// readExcludeFile returns the container numbers found in the file at path. Lines
+// are read case-insensitive and container numbers can be separated by the
+// configured separators, a space or a dash.
+func readExcludeFile(path string, config *configs.Config) ([]cont.Number, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	extractor := cont.NewExtractor(config.SepOE(), config.SepES(), config.SepSC(), " ", "-")
+	var numbers []cont.Number
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		for _, extracted := range extractor.Extract(strings.ToUpper(scanner.Text())) {
+			numbers = append(numbers, extracted.Number)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return numbers, nil
+}
+
+// fictitiousOwnerCompany is the company of fictitious owners.
+const fictitiousOwnerCompany = "Fictitious owner"
+
+// newFictitiousOwners returns count owners with owner codes picked at random of all owner
+// codes that are neither registered nor blocked. The owners are sorted by owner code.
+func newFictitiousOwners(ownerDecoder data.OwnerDecoder, count int, r *rand.Rand) ([]cont.Owner, error) {
+	blocker, _ := ownerDecoder.(cont.OwnerBlocker)
+	var codes []string
+	for code := range cont.AllOwnerCodes() {
+		if found, _ := ownerDecoder.Decode(code); found {
+			continue
+		}
+		if blocker != nil {
+			if blocked, _ := blocker.Blocked(code); blocked {
+				continue
+			}
+		}
+		codes = append(codes, code)
+	}
+	if count > len(codes) {
+		return nil, fmt.Errorf("count %d of fictitious owners exceeds limit of %d unregistered owner codes", count, len(codes))
+	}
+	r.Shuffle(len(codes), func(i, j int) { codes[i], codes[j] = codes[j], codes[i] })
+	codes = codes[:count]
+	slices.Sort(codes)
+
+	owners := make([]cont.Owner, 0, count)
+	for _, code := range codes {
+		owners = append(owners, cont.Owner{Code: code, Company: fictitiousOwnerCompany})
+	}
+	return owners, nil
+}
+
+// writeFictitiousOwnersFile writes owners to a new file at path.
+func writeFictitiousOwnersFile(writeOwnersCSV data.WriteOwnersCSVFunc, owners []cont.Owner, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeOwnersCSV(owners, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// resolveOwnerCodes returns the unique owner codes of codes or of all owners if there are
+// no codes. With countries or companyRegex only owner codes of matching owners are returned.
+func resolveOwnerCodes(ownerDecoder data.OwnerDecoder, codes, countries []string, companyRegex *regexp.Regexp) ([]string, error) {
+	if len(countries) == 0 && companyRegex == nil {
+		if len(codes) == 0 {
+			return ownerDecoder.GetAllOwnerCodes(), nil
+		}
+		return uniqueOwnerCodes(codes), nil
+	}
+
+	var owners []cont.Owner
+	if len(codes) == 0 {
+		owners = ownerDecoder.GetAllOwners()
+	} else {
+		for _, code := range uniqueOwnerCodes(codes) {
+			if found, owner := ownerDecoder.Decode(code); found {
+				owners = append(owners, owner)
+			}
+		}
+	}
+
+	var countryNames []string
+	for _, country := range countries {
+		if name, ok := data.CountryName(strings.ToUpper(strings.TrimSpace(country))); ok {
+			country = name
+		}
+		countryNames = append(countryNames, normalize(country))
+	}
+
+	var filtered []string
+	for _, owner := range owners {
+		if len(countryNames) > 0 && !slices.Contains(countryNames, normalize(owner.Country)) {
+			continue
+		}
+		if companyRegex != nil && !companyRegex.MatchString(owner.Company) {
+			continue
+		}
+		filtered = append(filtered, owner.Code)
+	}
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("no owners match the filters")
+	}
+	return filtered, nil
+}
+
+// uniqueOwnerCodes returns codes without duplicates in order of their first occurrence.
+func uniqueOwnerCodes(codes []string) []string {
+	seen := map[string]bool{}
+	var unique []string
+	for _, code := range codes {
+		if !seen[code] {
+			seen[code] = true
+			unique = append(unique, code)
+		}
+	}
+	return unique
+}
+
+const sizeTypeRandom = "random"
+
+// sizeTypeCodes returns the size and type codes and their weights for generation. Without
+// sizeType and sizeTypeFile no codes are returned.
+func sizeTypeCodes(decoders sizeTypeDecoders, sizeType, sizeTypeFile string) ([]string, []int, error) {
+	switch {
+	case sizeTypeFile != "":
+		codes, weights, err := readSizeTypeFile(sizeTypeFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, code := range codes {
+			if err := isKnownSizeTypeCode(decoders, code); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", sizeTypeFile, err)
+			}
+		}
+		return codes, weights, nil
+	case sizeType == sizeTypeRandom:
+		var codes []string
+		for _, length := range decoders.lengthDecoder.AllLengthCodes() {
+			for _, heightWidth := range decoders.heightWidthDecoder.AllHeightWidthCodes() {
+				for _, typeCode := range decoders.typeDecoder.AllTypeCodes() {
+					codes = append(codes, length+heightWidth+typeCode)
+				}
+			}
+		}
+		return codes, nil, nil
+	case sizeType != "":
+		code := strings.ToUpper(sizeType)
+		if err := isKnownSizeTypeCode(decoders, code); err != nil {
+			return nil, nil, err
+		}
+		return []string{code}, nil, nil
+	}
+	return nil, nil, nil
+}
+
+// isKnownSizeTypeCode returns nil if code has a known length, height and width and type code.
+func isKnownSizeTypeCode(decoders sizeTypeDecoders, code string) error {
+	if err := cont.IsSizeTypeCode(code); err != nil {
+		return err
+	}
+	if found, _ := decoders.lengthDecoder.Decode(code[0:1]); !found {
+		return fmt.Errorf("%s has unknown length code %s", code, code[0:1])
+	}
+	if found, _, _ := decoders.heightWidthDecoder.Decode(code[1:2]); !found {
+		return fmt.Errorf("%s has unknown height and width code %s", code, code[1:2])
+	}
+	if found, _, _ := decoders.typeDecoder.Decode(code[2:4]); !found {
+		return fmt.Errorf("%s has unknown type code %s", code, code[2:4])
+	}
+	return nil
+}
+
+// readSizeTypeFile returns the size and type codes and their weights of the file at path.
+// Every line has a code and an optional weight separated by semicolon or comma. The
+// default weight is 1. Empty lines and lines starting with # are ignored.
+func readSizeTypeFile(path string) ([]string, []int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	var codes []string
+	var weights []int
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		code, weightText, hasWeight := strings.Cut(strings.ReplaceAll(text, ",", ";"), ";")
+		weight := 1
+		if hasWeight {
+			weight, err = strconv.Atoi(strings.TrimSpace(weightText))
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: line %d: %w", path, line, err)
+			}
+		}
+		codes = append(codes, strings.ToUpper(strings.TrimSpace(code)))
+		weights = append(weights, weight)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(codes) == 0 {
+		return nil, nil, fmt.Errorf("%s: no size and type codes found", path)
+	}
+	return codes, weights, nil
+}
+
+// formatSizeTypeCode returns code with separators of config or an empty string
+// for an empty code.
+func formatSizeTypeCode(code string, config *configs.Config) string {
+	if code == "" {
+		return ""
+	}
+	return config.SepCS() + code[0:2] + config.SepST() + code[2:4]
+}
+
+// readGeneratorState returns the state of the file at path or nil if the file does not exist.
+func readGeneratorState(path string) (*cont.GeneratorState, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state cont.GeneratorState
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// writeGeneratorState writes state to a temporary file that replaces the file at path.
+func writeGeneratorState(path string, state cont.GeneratorState) error {
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -2,10 +2,16 @@ package cmd
 
 import (
 	"bytes"
+	"io"
 	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"testing"
 
 	"github.com/meyermarcel/icm/configs"
+	"github.com/meyermarcel/icm/data/file"
 )
 
 func Test_generateCmd(t *testing.T) {
@@ -41,6 +47,98 @@ func Test_generateCmd(t *testing.T) {
 			}},
 			false,
 			`ABC U 601921 5
+`,
+		},
+		{
+			"Generate 3 random container number with custom owners",
+			nil,
+			[]flag{
+				{
+					name:  "owner",
+					value: "ABC,DEF",
+				},
+				{
+					name:  "owner",
+					value: "GHI",
+				},
+				{
+					name:  "count",
+					value: "3",
+				},
+			},
+			false,
+			`GHI U 601921 4
+DEF U 784968 6
+GHI U 334138 2
+`,
+		},
+		{
+			"Generate 3 random container number with equipment category ID and size and type code",
+			nil,
+			[]flag{
+				{
+					name:  "count",
+					value: "3",
+				},
+				{
+					name:  "equipment-category-id",
+					value: "Z",
+				},
+				{
+					name:  "size-type",
+					value: "22g1",
+				},
+			},
+			false,
+			`NAR Z 601921 7   22 G1
+RAN Z 784968 7   22 G1
+RAN Z 334138 4   22 G1
+`,
+		},
+		{
+			"Generate 3 random container number with random size and type codes",
+			nil,
+			[]flag{
+				{
+					name:  "count",
+					value: "3",
+				},
+				{
+					name:  "size-type",
+					value: "random",
+				},
+				{
+					name:  configs.FlagNames.SepCS,
+					value: " ",
+				},
+				{
+					name:  configs.FlagNames.SepST,
+					value: "",
+				},
+			},
+			false,
+			`NAR U 601921 3 42G1
+RAN U 784968 3 22G1
+RAN U 334138 0 22R1
+`,
+		},
+		{
+			"Generate 3 random container number with seed",
+			nil,
+			[]flag{
+				{
+					name:  "count",
+					value: "3",
+				},
+				{
+					name:  "seed",
+					value: "42",
+				},
+			},
+			false,
+			`RAN U 846856 8
+RAN U 675224 0
+RAN U 160340 8
 `,
 		},
 		{
@@ -155,6 +253,81 @@ NAR U 000002 0
 			nil,
 			false,
 			`NAR***U+++601921‧‧‧3
+`,
+		},
+		{
+			"Generate 2 container numbers with template",
+			nil,
+			[]flag{
+				{"start", "0"},
+				{"end", "1"},
+				{"size-type", "22G1"},
+				{"format", `{{.OwnerCode}}{{.EquipCatID}} {{.SerialNumber}}-{{.CheckDigit}} {{.Size}}/{{.Type}}`},
+			},
+			false,
+			`NARU 000000-0 22/G1
+RANU 000001-4 22/G1
+`,
+		},
+		{
+			"Generate 2 container numbers with csv format",
+			nil,
+			[]flag{
+				{"start", "0"},
+				{"end", "1"},
+				{"format", "csv"},
+			},
+			false,
+			`container-number;owner-code;equipment-category-id;serial-number;check-digit;size-type
+NARU0000000;NAR;U;000000;0;
+RANU0000014;RAN;U;000001;4;
+`,
+		},
+		{
+			"Generate 4 container numbers with every second invalid",
+			nil,
+			[]flag{
+				{"start", "0"},
+				{"end", "3"},
+				{"invalid", "lower-case"},
+				{"invalid-ratio", "0.5"},
+			},
+			false,
+			`NAR U 000000 0;
+ran u 000001 4;lower-case
+NAR U 000002 0;
+ran u 000003 5;lower-case
+`,
+		},
+		{
+			"Generate 2 invalid container numbers with csv format",
+			nil,
+			[]flag{
+				{"start", "0"},
+				{"end", "1"},
+				{"invalid", "check-digit"},
+				{"format", "csv"},
+			},
+			false,
+			`container-number;owner-code;equipment-category-id;serial-number;check-digit;size-type;error-kind
+NARU0000007;NAR;U;000000;7;;check-digit
+RANU0000011;RAN;U;000001;1;;check-digit
+`,
+		},
+		{
+			"Generate 2 container numbers with json format",
+			nil,
+			[]flag{
+				{"start", "0"},
+				{"end", "1"},
+				{"size-type", "22G1"},
+				{"format", "json"},
+			},
+			false,
+			`[
+{"container-number":"NARU0000000","owner-code":"NAR","equipment-category-id":"U","serial-number":"000000","check-digit":0,"size-type":"22G1"},
+{"container-number":"RANU0000014","owner-code":"RAN","equipment-category-id":"U","serial-number":"000001","check-digit":4,"size-type":"22G1"}
+]
 `,
 		},
 	}
@@ -168,7 +341,17 @@ NAR U 000002 0
 				config.Map[override.name] = override.value
 			}
 
-			cmd := newGenerateCmd(writer, writerErr, config, &dummyOwnerDecodeUpdater{}, rand.New(rand.NewPCG(1, 0)))
+			d := decoders{
+				ownerDecodeUpdater: &dummyOwnerDecodeUpdater{},
+				equipCatDecoder:    &dummyEquipCatDecoder{},
+				sizeTypeDecoders: sizeTypeDecoders{
+					&dummyLengthDecoder{},
+					&dummyHeightWidthDecoder{},
+					&dummyTypeDecoder{},
+					&dummyLegacySizeTypeDecoder{},
+				},
+			}
+			cmd := newGenerateCmd(writer, writerErr, config, d, file.WriteOwnersCSV, rand.New(rand.NewPCG(1, 0)))
 			for _, flag := range tt.flags {
 				_ = cmd.Flags().Set(flag.name, flag.value)
 			}
@@ -181,3 +364,275 @@ NAR U 000002 0
 		})
 	}
 }
+
+func Test_resolveOwnerCodes(t *testing.T) {
+	tests := []struct {
+		name         string
+		codes        []string
+		countries    []string
+		companyRegex *regexp.Regexp
+		want         []string
+		wantErr      bool
+	}{
+		{"All owner codes without codes and filters", nil, nil, nil, []string{"HLC", "HLX", "MAE", "MSK", "ZIM"}, false},
+		{"Unique codes without filters", []string{"ABC", "DEF", "ABC"}, nil, nil, []string{"ABC", "DEF"}, false},
+		{"Filter by country code", nil, []string{"DE"}, nil, []string{"HLC", "HLX"}, false},
+		{"Filter by country names", nil, []string{"denmark", "Israel"}, nil, []string{"MAE", "MSK", "ZIM"}, false},
+		{"Filter by company", nil, nil, regexp.MustCompile(`^Maersk`), []string{"MAE", "MSK"}, false},
+		{"Filter by country and company", nil, []string{"DE"}, regexp.MustCompile(`Lloyd`), []string{"HLC"}, false},
+		{"Filter codes", []string{"ABC", "MSK", "ZIM"}, []string{"DK"}, nil, []string{"MSK"}, false},
+		{"No matching owners", nil, []string{"FR"}, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveOwnerCodes(testOwners, tt.codes, tt.countries, tt.companyRegex)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveOwnerCodes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveOwnerCodes() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_readOwnerCodesFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{"Read owner codes", "ABC\n\n# comment\n DEF \n", []string{"ABC", "DEF"}, false},
+		{"Read owner codes of owner CSV", "ABC;my company;my city;my country\nDEF,company,city,country\n", []string{"ABC", "DEF"}, false},
+		{"Invalid owner code", "ABC\nDE\n", nil, true},
+		{"No owner codes", "# comment\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "owners.txt")
+			_ = os.WriteFile(path, []byte(tt.content), 0o644)
+			got, err := readOwnerCodesFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readOwnerCodesFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readOwnerCodesFile() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_readSizeTypeFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantCodes   []string
+		wantWeights []int
+		wantErr     bool
+	}{
+		{"Read codes with weights", "# distribution\n22G1;60\n45r1, 30\n\nL5G1\n", []string{"22G1", "45R1", "L5G1"}, []int{60, 30, 1}, false},
+		{"Invalid weight", "22G1;many\n", nil, nil, true},
+		{"No codes", "# distribution\n", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "size-types.csv")
+			_ = os.WriteFile(path, []byte(tt.content), 0o644)
+			gotCodes, gotWeights, err := readSizeTypeFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readSizeTypeFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotCodes, tt.wantCodes) || !reflect.DeepEqual(gotWeights, tt.wantWeights) {
+				t.Errorf("readSizeTypeFile() got = %v %v, want %v %v", gotCodes, gotWeights, tt.wantCodes, tt.wantWeights)
+			}
+		})
+	}
+}
+
+func Test_formatValue_Set(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"Set csv", "csv", false},
+		{"Set template", "{{.ContNum}};{{.SizeType}}", false},
+		{"Return error for invalid template", "{{.ContNum", true},
+		{"Return error for unknown field", "{{.Unknown}}", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &formatValue{}
+			if err := f.Set(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("formatValue.Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_generateCmdState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	d := decoders{
+		ownerDecodeUpdater: &dummyOwnerDecodeUpdater{},
+		equipCatDecoder:    &dummyEquipCatDecoder{},
+	}
+	generate := func(flags map[string]string) string {
+		writer := &bytes.Buffer{}
+		config, _ := configs.ReadConfig(configs.DefaultConfig())
+		cmd := newGenerateCmd(writer, io.Discard, config, d, file.WriteOwnersCSV, rand.New(rand.NewPCG(1, 0)))
+		for name, value := range flags {
+			_ = cmd.Flags().Set(name, value)
+		}
+		if err := cmd.RunE(cmd, nil); err != nil {
+			t.Fatalf("got = %v, want no error", err)
+		}
+		return writer.String()
+	}
+
+	got := generate(map[string]string{"count": "2", "start": "999999", "state": stateFile}) +
+		generate(map[string]string{"count": "3", "state": stateFile})
+	want := `RAN U 999999 6
+NAR U 000000 0
+RAN U 000001 4
+NAR U 000002 0
+RAN U 000003 5
+`
+	if got != want {
+		t.Errorf("gotWriter = %v, want %v", got, want)
+	}
+
+	b, _ := os.ReadFile(stateFile)
+	wantState := `{
+  "owner-codes": [
+    "NAR",
+    "RAN"
+  ],
+  "owner-offset": 0,
+  "equipment-category-id": "U",
+  "random": false,
+  "rand-offset": 0,
+  "start": 999999,
+  "position": 4,
+  "generated": 5
+}
+`
+	if string(b) != wantState {
+		t.Errorf("state = %s, want %s", b, wantState)
+	}
+}
+
+func Test_generateCmdStateSizeType(t *testing.T) {
+	d := decoders{
+		ownerDecodeUpdater: &dummyOwnerDecodeUpdater{},
+		equipCatDecoder:    &dummyEquipCatDecoder{},
+		sizeTypeDecoders: sizeTypeDecoders{
+			&dummyLengthDecoder{},
+			&dummyHeightWidthDecoder{},
+			&dummyTypeDecoder{},
+			&dummyLegacySizeTypeDecoder{},
+		},
+	}
+	generate := func(flags map[string]string) (string, error) {
+		writer := &bytes.Buffer{}
+		config, _ := configs.ReadConfig(configs.DefaultConfig())
+		cmd := newGenerateCmd(writer, io.Discard, config, d, file.WriteOwnersCSV, rand.New(rand.NewPCG(1, 0)))
+		for name, value := range flags {
+			_ = cmd.Flags().Set(name, value)
+		}
+		err := cmd.RunE(cmd, nil)
+		return writer.String(), err
+	}
+
+	want, _ := generate(map[string]string{"count": "10", "size-type": "random"})
+
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	first, _ := generate(map[string]string{"count": "4", "size-type": "random", "state": stateFile})
+	second, err := generate(map[string]string{"count": "6", "size-type": "random", "state": stateFile})
+	if err != nil {
+		t.Fatalf("got = %v, want no error", err)
+	}
+	if got := first + second; got != want {
+		t.Errorf("gotWriter = %v, want %v", got, want)
+	}
+
+	if _, err := generate(map[string]string{"count": "1", "size-type": "random", "equipment-category-id": "Z", "state": stateFile}); err == nil {
+		t.Errorf("got no error for different equipment category ID, want error")
+	}
+	if _, err := generate(map[string]string{"count": "1", "state": stateFile}); err == nil {
+		t.Errorf("got no error for missing size and type codes, want error")
+	}
+}
+
+func Test_generateCmdExcludeFile(t *testing.T) {
+	excludeFile := filepath.Join(t.TempDir(), "fleet.csv")
+	_ = os.WriteFile(excludeFile, []byte("container;size-type\nRAN U 000001 4;22G1\nnaru0000020;45R1\n"), 0o644)
+
+	writer := &bytes.Buffer{}
+	config, _ := configs.ReadConfig(configs.DefaultConfig())
+	cmd := newGenerateCmd(writer, io.Discard, config, decoders{
+		ownerDecodeUpdater: &dummyOwnerDecodeUpdater{},
+		equipCatDecoder:    &dummyEquipCatDecoder{},
+	}, file.WriteOwnersCSV, rand.New(rand.NewPCG(1, 0)))
+	_ = cmd.Flags().Set("start", "0")
+	_ = cmd.Flags().Set("end", "3")
+	_ = cmd.Flags().Set("exclude-file", excludeFile)
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("got = %v, want no error", err)
+	}
+	want := `NAR U 000000 0
+RAN U 000003 5
+`
+	if got := writer.String(); got != want {
+		t.Errorf("gotWriter = %v, want %v", got, want)
+	}
+}
+
+func Test_generateCmdFictitiousOwners(t *testing.T) {
+	fictitiousOwnersFile := filepath.Join(t.TempDir(), "fictitious-owner.csv")
+
+	writer := &bytes.Buffer{}
+	config, _ := configs.ReadConfig(configs.DefaultConfig())
+	cmd := newGenerateCmd(writer, io.Discard, config, decoders{
+		ownerDecodeUpdater: &dummyOwnerDecodeUpdater{},
+		equipCatDecoder:    &dummyEquipCatDecoder{},
+	}, file.WriteOwnersCSV, rand.New(rand.NewPCG(1, 0)))
+	_ = cmd.Flags().Set("fictitious-owners", "2")
+	_ = cmd.Flags().Set("fictitious-owners-file", fictitiousOwnersFile)
+	_ = cmd.Flags().Set("start", "0")
+	_ = cmd.Flags().Set("end", "1")
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("got = %v, want no error", err)
+	}
+	want := `DXI U 000000 0
+JDX U 000001 3
+`
+	if got := writer.String(); got != want {
+		t.Errorf("gotWriter = %v, want %v", got, want)
+	}
+	b, _ := os.ReadFile(fictitiousOwnersFile)
+	wantFile := `DXI;Fictitious owner;;
+JDX;Fictitious owner;;
+`
+	if string(b) != wantFile {
+		t.Errorf("fictitious owners file = %s, want %s", b, wantFile)
+	}
+}
+
+func Test_newFictitiousOwners(t *testing.T) {
+	owners, err := newFictitiousOwners(&dummyOwnerDecodeUpdater{}, 17575, rand.New(rand.NewPCG(1, 0)))
+	if err != nil {
+		t.Fatalf("got = %v, want no error", err)
+	}
+	for _, owner := range owners {
+		if owner.Code == "ABC" {
+			t.Errorf("newFictitiousOwners() returned registered owner code %s", owner.Code)
+		}
+	}
+	if _, err := newFictitiousOwners(&dummyOwnerDecodeUpdater{}, 17576, rand.New(rand.NewPCG(1, 0))); err == nil {
+		t.Errorf("newFictitiousOwners() got no error for count exceeding unregistered owner codes")
+	}
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/meyermarcel/icm/cont"
+	"github.com/meyermarcel/icm/data"
+)
+
+// Kinds of errors injected into generated container numbers.
+const (
+	invalidAll               = "all"
+	invalidCheckDigit        = "check-digit"
+	invalidTransposition     = "transposition"
+	invalidUnregisteredOwner = "unregistered-owner"
+	invalidEquipCatID        = "equipment-category-id"
+	invalidMissingDigit      = "missing-digit"
+	invalidOCRConfusion      = "ocr-confusion"
+	invalidLowerCase         = "lower-case"
+	invalidExtraSeparator    = "extra-separator"
+)
+
+var invalidKinds = []string{
+	invalidCheckDigit,
+	invalidTransposition,
+	invalidUnregisteredOwner,
+	invalidEquipCatID,
+	invalidMissingDigit,
+	invalidOCRConfusion,
+	invalidLowerCase,
+	invalidExtraSeparator,
+}
+
+type invalidKindsValue struct {
+	values []string
+}
+
+func (i *invalidKindsValue) String() string {
+	return strings.Join(i.values, ",")
+}
+
+func (i *invalidKindsValue) Set(value string) error {
+	for _, kind := range strings.Split(value, ",") {
+		switch {
+		case kind == invalidAll:
+			i.values = append(i.values, invalidKinds...)
+		case slices.Contains(invalidKinds, kind):
+			i.values = append(i.values, kind)
+		default:
+			return fmt.Errorf("%s is not %s or %s", kind, invalidAll, strings.Join(invalidKinds, ", "))
+		}
+	}
+	return nil
+}
+
+func (*invalidKindsValue) Type() string {
+	return "strings"
+}
+
+// ratioScale is the scale of a ratio as integer, so a ratio has up to 6 decimal places.
+const ratioScale = 1000000
+
+type ratioValue struct {
+	value float64
+}
+
+// scaled returns the ratio as integer of ratioScale.
+func (r *ratioValue) scaled() int {
+	return int(math.Round(r.value * ratioScale))
+}
+
+func (r *ratioValue) String() string {
+	return strconv.FormatFloat(r.value, 'f', -1, 64)
+}
+
+func (r *ratioValue) Set(value string) error {
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+	if ratio <= 0 || ratio > 1 {
+		return fmt.Errorf("%s is not greater than 0 and at most 1", value)
+	}
+	if math.Round(ratio*ratioScale) < 1 {
+		return fmt.Errorf("%s is less than %g", value, 1.0/ratioScale)
+	}
+	r.value = ratio
+	return nil
+}
+
+func (*ratioValue) Type() string {
+	return "float"
+}
+
+// invalidRecord is a generated container number with the kind of the injected error.
+type invalidRecord struct {
+	generatedNumber
+	ErrorKind string `json:"error-kind"`
+}
+
+var invalidRecordHeader = append(slices.Clone(generatedNumberHeader), "error-kind")
+
+func (i invalidRecord) record() []string {
+	return append(i.generatedNumber.record(), i.ErrorKind)
+}
+
+// ocrConfusions maps letters and digits to characters that are often confused by OCR.
+var ocrConfusions = map[byte]byte{
+	'O': '0', 'I': '1', 'Z': '2', 'A': '4', 'S': '5', 'G': '6', 'T': '7', 'B': '8',
+	'0': 'O', '1': 'I', '2': 'Z', '4': 'A', '5': 'S', '6': 'G', '7': 'T', '8': 'B',
+}
+
+var extraSeparators = []string{" ", "-", "/", "."}
+
+// invalidInjector injects errors of kinds into a ratio of generated container numbers.
+type invalidInjector struct {
+	rand  *rand.Rand
+	kinds []string
+	// ratio is the ratio of invalid container numbers scaled by ratioScale.
+	// Integers avoid rounding errors of floating point numbers.
+	ratio           int
+	ownerDecoder    data.OwnerDecoder
+	equipCatDecoder data.EquipCatDecoder
+	generated       int
+}
+
+// next returns g with an injected error and its kind or g unchanged. Errors are evenly
+// spread, so after n container numbers n * ratio rounded down errors are injected.
+func (inj *invalidInjector) next(g generatedNumber) generatedNumber {
+	inj.generated++
+	if inj.generated*inj.ratio/ratioScale == (inj.generated-1)*inj.ratio/ratioScale {
+		return g
+	}
+	injected, kind := inj.inject(g, inj.kinds[inj.rand.IntN(len(inj.kinds))])
+	injected.ErrorKind = kind
+	return injected
+}
+
+// inject returns g with an error of kind. If an error of kind cannot be injected into g,
+// a wrong check digit is injected. The returned kind is the kind of the injected error.
+func (inj *invalidInjector) inject(g generatedNumber, kind string) (generatedNumber, string) {
+	switch kind {
+	case invalidTransposition:
+		if injected, ok := inj.transpose(g); ok {
+			return injected, kind
+		}
+	case invalidUnregisteredOwner:
+		for range 1000 {
+			code := randomLetters(inj.rand, 3)
+			if found, _ := inj.ownerDecoder.Decode(code); !found {
+				g.OwnerCode = code
+				return withCalcCheckDigit(g), kind
+			}
+		}
+	case invalidEquipCatID:
+		var unknown []string
+		for _, id := range strings.Split(randomLettersAlphabet, "") {
+			if !slices.Contains(inj.equipCatDecoder.AllCatIDs(), id) {
+				unknown = append(unknown, id)
+			}
+		}
+		if len(unknown) > 0 {
+			g.EquipCatID = unknown[inj.rand.IntN(len(unknown))]
+			return withCalcCheckDigit(g), kind
+		}
+	case invalidMissingDigit:
+		i := inj.rand.IntN(len(g.SerialNumber))
+		g.SerialNumber = g.SerialNumber[:i] + g.SerialNumber[i+1:]
+		return withContNum(g), kind
+	case invalidOCRConfusion:
+		if injected, ok := inj.confuse(g); ok {
+			return injected, kind
+		}
+	case invalidLowerCase:
+		g.OwnerCode = strings.ToLower(g.OwnerCode)
+		g.EquipCatID = strings.ToLower(g.EquipCatID)
+		return withContNum(g), kind
+	case invalidExtraSeparator:
+		i := inj.rand.IntN(len(g.SerialNumber)-1) + 1
+		g.SerialNumber = g.SerialNumber[:i] + extraSeparators[inj.rand.IntN(len(extraSeparators))] + g.SerialNumber[i:]
+		return withContNum(g), kind
+	}
+	g.CheckDigit = (g.CheckDigit + inj.rand.IntN(9) + 1) % 10
+	return withContNum(g), invalidCheckDigit
+}
+
+// transpose swaps two adjacent digits of serial number and check digit, so the check
+// digit is wrong. It returns false if no swap results in a wrong check digit.
+func (inj *invalidInjector) transpose(g generatedNumber) (generatedNumber, bool) {
+	digits := []byte(g.SerialNumber + strconv.Itoa(g.CheckDigit))
+	var positions []int
+	for i := range len(digits) - 1 {
+		if digits[i] == digits[i+1] {
+			continue
+		}
+		swapped := slices.Clone(digits)
+		swapped[i], swapped[i+1] = swapped[i+1], swapped[i]
+		serialNum, _ := strconv.Atoi(string(swapped[:6]))
+		calc := cont.CalcCheckDigit(g.OwnerCode, rune(g.EquipCatID[0]), serialNum)
+		if calc%10 != int(swapped[6]-'0') {
+			positions = append(positions, i)
+		}
+	}
+	if len(positions) == 0 {
+		return g, false
+	}
+	i := positions[inj.rand.IntN(len(positions))]
+	digits[i], digits[i+1] = digits[i+1], digits[i]
+	g.SerialNumber = string(digits[:6])
+	g.CheckDigit = int(digits[6] - '0')
+	return withContNum(g), true
+}
+
+// confuse replaces a letter of the owner code or a digit of the serial number with a
+// character often confused by OCR. It returns false if no character can be replaced.
+func (inj *invalidInjector) confuse(g generatedNumber) (generatedNumber, bool) {
+	chars := []byte(g.OwnerCode + g.SerialNumber)
+	var positions []int
+	for i, c := range chars {
+		if _, ok := ocrConfusions[c]; ok {
+			positions = append(positions, i)
+		}
+	}
+	if len(positions) == 0 {
+		return g, false
+	}
+	i := positions[inj.rand.IntN(len(positions))]
+	chars[i] = ocrConfusions[chars[i]]
+	g.OwnerCode = string(chars[:3])
+	g.SerialNumber = string(chars[3:])
+	return withContNum(g), true
+}
+
+const randomLettersAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+func randomLetters(r *rand.Rand, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = randomLettersAlphabet[r.IntN(len(randomLettersAlphabet))]
+	}
+	return string(b)
+}
+
+// withCalcCheckDigit returns g with the calculated check digit of owner code,
+// equipment category ID and serial number.
+func withCalcCheckDigit(g generatedNumber) generatedNumber {
+	serialNum, _ := strconv.Atoi(g.SerialNumber)
+	g.CheckDigit = cont.CalcCheckDigit(g.OwnerCode, rune(g.EquipCatID[0]), serialNum) % 10
+	return withContNum(g)
+}
+
+// withContNum returns g with the container number of its parts.
+func withContNum(g generatedNumber) generatedNumber {
+	g.ContNum = g.OwnerCode + g.EquipCatID + g.SerialNumber + strconv.Itoa(g.CheckDigit)
+	return g
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/meyermarcel/icm/cont"
+)
+
+func Test_invalidInjector_inject(t *testing.T) {
+	abc := cont.Number{OwnerCode: "ABC", EquipCatID: 'U', SerialNumber: 123456}
+	tests := []struct {
+		name        string
+		number      cont.Number
+		kind        string
+		wantContNum string
+		wantKind    string
+	}{
+		{"Inject wrong check digit", abc, invalidCheckDigit, "ABCU1234566", invalidCheckDigit},
+		{"Inject transposition", abc, invalidTransposition, "ABCU1235460", invalidTransposition},
+		{"Inject wrong check digit instead of transposition", cont.Number{OwnerCode: "ABC", EquipCatID: 'U', SerialNumber: 0}, invalidTransposition, "ABCU0000006", invalidCheckDigit},
+		{"Inject unregistered owner", abc, invalidUnregisteredOwner, "PCSU1234560", invalidUnregisteredOwner},
+		{"Inject unknown equipment category ID", abc, invalidEquipCatID, "ABCO1234567", invalidEquipCatID},
+		{"Inject missing digit", abc, invalidMissingDigit, "ABCU123560", invalidMissingDigit},
+		{"Inject OCR confusion", abc, invalidOCRConfusion, "ABCU123A560", invalidOCRConfusion},
+		{"Inject wrong check digit instead of OCR confusion", cont.Number{OwnerCode: "CDE", EquipCatID: 'U', SerialNumber: 939393}, invalidOCRConfusion, "CDEU9393936", invalidCheckDigit},
+		{"Inject lower case", abc, invalidLowerCase, "abcu1234560", invalidLowerCase},
+		{"Inject extra separator", abc, invalidExtraSeparator, "ABCU123/4560", invalidExtraSeparator},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inj := &invalidInjector{
+				rand:            rand.New(rand.NewPCG(1, 0)),
+				ownerDecoder:    &dummyOwnerDecodeUpdater{},
+				equipCatDecoder: &dummyEquipCatDecoder{},
+			}
+			got, gotKind := inj.inject(newGeneratedNumber(tt.number, ""), tt.kind)
+			if got.ContNum != tt.wantContNum {
+				t.Errorf("inject() got = %v, want %v", got.ContNum, tt.wantContNum)
+			}
+			if gotKind != tt.wantKind {
+				t.Errorf("inject() gotKind = %v, want %v", gotKind, tt.wantKind)
+			}
+		})
+	}
+}
+
+func Test_invalidInjector_next(t *testing.T) {
+	tests := []struct {
+		name  string
+		ratio int
+		want  []bool
+	}{
+		{"Inject into every container number", ratioScale, []bool{true, true, true, true}},
+		{"Inject into every second container number", ratioScale / 2, []bool{false, true, false, true}},
+		{"Inject into every fourth container number", ratioScale / 4, []bool{false, false, false, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inj := &invalidInjector{
+				rand:  rand.New(rand.NewPCG(1, 0)),
+				kinds: []string{invalidLowerCase},
+				ratio: tt.ratio,
+			}
+			var got []bool
+			for range tt.want {
+				g := inj.next(newGeneratedNumber(cont.Number{OwnerCode: "ABC", EquipCatID: 'U', SerialNumber: 123456}, ""))
+				got = append(got, g.ErrorKind != "")
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("next() injected = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_invalidInjector_nextRatio(t *testing.T) {
+	tests := []struct {
+		name  string
+		ratio string
+		count int
+		want  int
+	}{
+		{"Inject exact ratio 0.7", "0.7", 30, 21},
+		{"Inject exact ratio 0.1", "0.1", 100, 10},
+		{"Inject exact ratio 0.3", "0.3", 10, 3},
+		{"Inject ratio rounded down", "0.3", 9, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ratio := ratioValue{}
+			if err := ratio.Set(tt.ratio); err != nil {
+				t.Fatal(err)
+			}
+			inj := &invalidInjector{
+				rand:  rand.New(rand.NewPCG(1, 0)),
+				kinds: []string{invalidLowerCase},
+				ratio: ratio.scaled(),
+			}
+			got := 0
+			for range tt.count {
+				if inj.next(newGeneratedNumber(cont.Number{OwnerCode: "ABC", EquipCatID: 'U', SerialNumber: 123456}, "")).ErrorKind != "" {
+					got++
+				}
+			}
+			if got != tt.want {
+				t.Errorf("next() injected %d errors, want %d", got, tt.want)
+			}
+		})
+	}
+}
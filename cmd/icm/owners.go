@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/meyermarcel/icm/configs"
+	"github.com/meyermarcel/icm/cont"
+	"github.com/meyermarcel/icm/data"
+	"github.com/spf13/cobra"
+)
+
+type ownerRecord struct {
+	Code    string `json:"owner-code"`
+	Company string `json:"company"`
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+var ownerRecordHeader = []string{"owner-code", "company", "city", "country"}
+
+func (o ownerRecord) record() []string {
+	return []string{o.Code, o.Company, o.City, o.Country}
+}
+
+func newOwnersCmd(writer io.Writer, ownerDecoder data.OwnerDecoder) *cobra.Command {
+	ownersCmd := &cobra.Command{
+		Use:   "owners",
+		Short: "Search, show and list owners",
+		Long: `Search, show and list owners of the owner sources configured in
+
+  ` + ownerSourcesPath + `
+
+Blocked owner codes are not listed.
+
+Owners are written as CSV, JSON or newline delimited JSON.`,
+		Args: cobra.NoArgs,
+	}
+
+	ownersCmd.AddCommand(newOwnersSearchCmd(writer, ownerDecoder))
+	ownersCmd.AddCommand(newOwnersShowCmd(writer, ownerDecoder))
+	ownersCmd.AddCommand(newOwnersListCmd(writer, ownerDecoder))
+
+	return ownersCmd
+}
+
+func newOwnersSearchCmd(writer io.Writer, ownerDecoder data.OwnerDecoder) *cobra.Command {
+	output := recordOutputValue{value: outputCSV}
+
+	searchCmd := &cobra.Command{
+		Use:   "search QUERY...",
+		Short: "Search owners by company, city or country",
+		Long: `Search owners by company, city or country.
+
+The search is case-insensitive and tolerates differences in accents,
+punctuation and spaces. An owner is found if every word of the query is
+part of the company, city or country. Owners with a matching company are
+listed first.`,
+		Example: `icm owners search maersk
+icm owners search hapag lloyd
+# Search with JSON output
+icm owners search --output json hamburg`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			noHeader, _ := cmd.Flags().GetBool(configs.FlagNames.NoHeader)
+			owners := searchOwners(ownerDecoder.GetAllOwners(), strings.Join(args, " "))
+			return printOwners(newRecordPrinter(writer, output.value, noHeader, ownerRecordHeader), owners)
+		},
+	}
+	addOwnersOutputFlags(searchCmd, &output)
+
+	return searchCmd
+}
+
+func newOwnersShowCmd(writer io.Writer, ownerDecoder data.OwnerDecoder) *cobra.Command {
+	output := recordOutputValue{value: outputCSV}
+
+	showCmd := &cobra.Command{
+		Use:   "show CODE...",
+		Short: "Show owners of owner codes",
+		Long:  "Show owners of owner codes. Blocked owner codes are not shown.",
+		Example: `icm owners show MAE
+icm owners show MAE HLC --output json`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			noHeader, _ := cmd.Flags().GetBool(configs.FlagNames.NoHeader)
+			var owners []cont.Owner
+			for _, code := range args {
+				found, owner := ownerDecoder.Decode(strings.ToUpper(code))
+				if !found {
+					return fmt.Errorf("%s is not a registered owner code", code)
+				}
+				owners = append(owners, owner)
+			}
+			return printOwners(newRecordPrinter(writer, output.value, noHeader, ownerRecordHeader), owners)
+		},
+	}
+	addOwnersOutputFlags(showCmd, &output)
+
+	return showCmd
+}
+
+func newOwnersListCmd(writer io.Writer, ownerDecoder data.OwnerDecoder) *cobra.Command {
+	output := recordOutputValue{value: outputCSV}
+
+	var company, city, country string
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List owners",
+		Long: `List owners sorted by owner code.
+
+Owners can be filtered by company, city and country. Filters are matched
+like the query of the search command.`,
+		Example: `icm owners list
+icm owners list --country germany
+icm owners list --city hamburg --output ndjson`,
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			noHeader, _ := cmd.Flags().GetBool(configs.FlagNames.NoHeader)
+			var owners []cont.Owner
+			for _, owner := range ownerDecoder.GetAllOwners() {
+				if matchesQuery(owner.Company, company) &&
+					matchesQuery(owner.City, city) &&
+					matchesQuery(owner.Country, country) {
+					owners = append(owners, owner)
+				}
+			}
+			return printOwners(newRecordPrinter(writer, output.value, noHeader, ownerRecordHeader), owners)
+		},
+	}
+	listCmd.Flags().SortFlags = false
+	listCmd.Flags().StringVar(&company, "company", "", "lists only owners with matching company")
+	listCmd.Flags().StringVar(&city, "city", "", "lists only owners with matching city")
+	listCmd.Flags().StringVar(&country, "country", "", "lists only owners with matching country")
+	addOwnersOutputFlags(listCmd, &output)
+
+	return listCmd
+}
+
+func addOwnersOutputFlags(cmd *cobra.Command, output *recordOutputValue) {
+	cmd.Flags().Var(output, configs.FlagNames.Output,
+		fmt.Sprintf("sets output to %s, %s or %s", outputCSV, outputJSON, outputNDJSON))
+	_ = cmd.RegisterFlagCompletionFunc(configs.FlagNames.Output, func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{outputCSV, outputJSON, outputNDJSON}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().Bool(configs.FlagNames.NoHeader, configs.DefaultValues.NoHeader,
+		"omits header of CSV output")
+}
+
+func printOwners(printer *recordPrinter, owners []cont.Owner) error {
+	for _, owner := range owners {
+		if err := printer.print(ownerRecord(owner)); err != nil {
+			return err
+		}
+	}
+	return printer.close()
+}
+
+// searchOwners returns the owners matching query. Owners with an equal company are
+// first, followed by owners with a company starting with query, owners with a matching
+// company and owners with a matching city or country. Owners with the same rank are
+// sorted by owner code.
+func searchOwners(owners []cont.Owner, query string) []cont.Owner {
+	normQuery := normalize(query)
+
+	type rankedOwner struct {
+		owner cont.Owner
+		rank  int
+	}
+	var found []rankedOwner
+	for _, owner := range owners {
+		company := normalize(owner.Company)
+		switch {
+		case company == normQuery:
+			found = append(found, rankedOwner{owner, 0})
+		case strings.HasPrefix(company, normQuery):
+			found = append(found, rankedOwner{owner, 1})
+		case matchesQuery(owner.Company, query):
+			found = append(found, rankedOwner{owner, 2})
+		case matchesQuery(strings.Join([]string{owner.Company, owner.City, owner.Country}, " "), query):
+			found = append(found, rankedOwner{owner, 3})
+		}
+	}
+	slices.SortStableFunc(found, func(a, b rankedOwner) int {
+		return cmp.Or(cmp.Compare(a.rank, b.rank), strings.Compare(a.owner.Code, b.owner.Code))
+	})
+
+	result := make([]cont.Owner, 0, len(found))
+	for _, f := range found {
+		result = append(result, f.owner)
+	}
+	return result
+}
+
+// matchesQuery returns true if every word of query is part of text or if query
+// without spaces is part of text without spaces. An empty query matches every text.
+func matchesQuery(text, query string) bool {
+	normText := normalize(text)
+	normQuery := normalize(query)
+	if normQuery == "" {
+		return true
+	}
+	allWords := true
+	for _, word := range strings.Fields(normQuery) {
+		if !strings.Contains(normText, word) {
+			allWords = false
+			break
+		}
+	}
+	return allWords || strings.Contains(
+		strings.ReplaceAll(normText, " ", ""),
+		strings.ReplaceAll(normQuery, " ", ""))
+}
+
+// foldings has replacements for letters with diacritics.
+var foldings = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c", 'č': "c", 'ć': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ě': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ı': "i",
+	'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'œ': "oe",
+	'ř': "r", 'š': "s", 'ś': "s", 'ş': "s", 'ß': "ss",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ů': "u",
+	'ý': "y", 'ÿ': "y", 'ž': "z", 'ź': "z", 'ż': "z", 'ł': "l", 'đ': "d",
+}
+
+// normalize returns s in lower case with letters without diacritics, without punctuation
+// and with single spaces between words.
+func normalize(s string) string {
+	b := strings.Builder{}
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case foldings[r] != "":
+			b.WriteString(foldings[r])
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
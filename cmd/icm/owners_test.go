@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/meyermarcel/icm/cont"
+)
+
+type listOwnerDecoder []cont.Owner
+
+func (l listOwnerDecoder) Decode(code string) (bool, cont.Owner) {
+	for _, owner := range l {
+		if owner.Code == code {
+			return true, owner
+		}
+	}
+	return false, cont.Owner{}
+}
+
+func (l listOwnerDecoder) GetAllOwnerCodes() []string {
+	var codes []string
+	for _, owner := range l {
+		codes = append(codes, owner.Code)
+	}
+	return codes
+}
+
+func (listOwnerDecoder) Source(_ string) string {
+	return ""
+}
+
+func (l listOwnerDecoder) GetAllOwners() []cont.Owner {
+	return l
+}
+
+var testOwners = listOwnerDecoder{
+	{Code: "HLC", Company: "Hapag-Lloyd AG", City: "Hamburg", Country: "Germany"},
+	{Code: "HLX", Company: "Hamburg Süd", City: "Hamburg", Country: "Germany"},
+	{Code: "MAE", Company: "Maersk A/S", City: "Copenhagen", Country: "Denmark"},
+	{Code: "MSK", Company: "Maersk", City: "Copenhagen", Country: "Denmark"},
+	{Code: "ZIM", Company: "Zim Integrated Shipping Services", City: "Haifa", Country: "Israel"},
+}
+
+func Test_ownersCmd(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantWriter string
+	}{
+		{
+			"Search owners by company with equal company first",
+			[]string{"search", "maersk"},
+			false,
+			`owner-code;company;city;country
+MSK;Maersk;Copenhagen;Denmark
+MAE;Maersk A/S;Copenhagen;Denmark
+`,
+		},
+		{
+			"Search owners case-insensitive without punctuation",
+			[]string{"search", "HAPAG", "lloyd"},
+			false,
+			`owner-code;company;city;country
+HLC;Hapag-Lloyd AG;Hamburg;Germany
+`,
+		},
+		{
+			"Search owners with matching company before matching city",
+			[]string{"search", "hamburg"},
+			false,
+			`owner-code;company;city;country
+HLX;Hamburg Süd;Hamburg;Germany
+HLC;Hapag-Lloyd AG;Hamburg;Germany
+`,
+		},
+		{
+			"Search owners without accents and with json output",
+			[]string{"search", "hamburg sud", "--output", "json"},
+			false,
+			`[
+{"owner-code":"HLX","company":"Hamburg Süd","city":"Hamburg","country":"Germany"}
+]
+`,
+		},
+		{
+			"Search owners with substring of country",
+			[]string{"search", "isra", "--no-header"},
+			false,
+			`ZIM;Zim Integrated Shipping Services;Haifa;Israel
+`,
+		},
+		{
+			"Search no owners",
+			[]string{"search", "nothing", "--output", "ndjson"},
+			false,
+			``,
+		},
+		{
+			"Show owners",
+			[]string{"show", "msk", "HLC", "--output", "ndjson"},
+			false,
+			`{"owner-code":"MSK","company":"Maersk","city":"Copenhagen","country":"Denmark"}
+{"owner-code":"HLC","company":"Hapag-Lloyd AG","city":"Hamburg","country":"Germany"}
+`,
+		},
+		{
+			"Show not registered owner",
+			[]string{"show", "MSK", "XYZ"},
+			true,
+			``,
+		},
+		{
+			"List owners",
+			[]string{"list", "--no-header"},
+			false,
+			`HLC;Hapag-Lloyd AG;Hamburg;Germany
+HLX;Hamburg Süd;Hamburg;Germany
+MAE;Maersk A/S;Copenhagen;Denmark
+MSK;Maersk;Copenhagen;Denmark
+ZIM;Zim Integrated Shipping Services;Haifa;Israel
+`,
+		},
+		{
+			"List owners with filters",
+			[]string{"list", "--country", "germany", "--company", "lloyd"},
+			false,
+			`owner-code;company;city;country
+HLC;Hapag-Lloyd AG;Hamburg;Germany
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+			cmd := newOwnersCmd(writer, testOwners)
+			cmd.SetArgs(tt.args)
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			if err := cmd.Execute(); (err != nil) != tt.wantErr {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("gotWriter = %v, want %v", gotWriter, tt.wantWriter)
+			}
+		})
+	}
+}
+
+func Test_normalize(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"Normalize case and accents", "Hamburg Süd", "hamburg sud"},
+		{"Normalize punctuation", "A.P. Møller-Mærsk A/S", "ap mollermaersk as"},
+		{"Normalize spaces", "  Zim \t Integrated ", "zim integrated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalize(tt.s); got != tt.want {
+				t.Errorf("normalize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
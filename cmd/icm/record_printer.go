@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/meyermarcel/icm/input"
+)
+
+type recordOutputValue struct {
+	value string
+}
+
+func (o *recordOutputValue) String() string {
+	return o.value
+}
+
+func (o *recordOutputValue) Set(value string) error {
+	switch value {
+	case outputCSV, outputJSON, outputNDJSON:
+		o.value = value
+		return nil
+	}
+	return fmt.Errorf("%s is not %s, %s or %s", value, outputCSV, outputJSON, outputNDJSON)
+}
+
+func (*recordOutputValue) Type() string {
+	return "string"
+}
+
+// record is a value that is printed as CSV record or as JSON object.
+type record interface {
+	record() []string
+}
+
+// recordPrinter writes records as CSV, JSON or newline delimited JSON.
+type recordPrinter struct {
+	noHeader   bool
+	header     []string
+	csvWriter  *csv.Writer
+	jsonWriter *input.JSONWriter
+	printed    bool
+}
+
+func newRecordPrinter(writer io.Writer, output string, noHeader bool, header []string) *recordPrinter {
+	p := &recordPrinter{noHeader: noHeader, header: header}
+	switch output {
+	case outputJSON:
+		p.jsonWriter = input.NewJSONWriter(writer)
+	case outputNDJSON:
+		p.jsonWriter = input.NewNDJSONWriter(writer)
+	default:
+		p.csvWriter = csv.NewWriter(writer)
+		p.csvWriter.Comma = ';'
+	}
+	return p
+}
+
+func (p *recordPrinter) print(r record) error {
+	if p.jsonWriter != nil {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		return p.jsonWriter.WriteObject(b)
+	}
+	if err := p.printHeader(); err != nil {
+		return err
+	}
+	return p.csvWriter.Write(r.record())
+}
+
+// printHeader writes the CSV header once unless noHeader is set.
+func (p *recordPrinter) printHeader() error {
+	if p.printed || p.noHeader {
+		p.printed = true
+		return nil
+	}
+	p.printed = true
+	return p.csvWriter.Write(p.header)
+}
+
+// close writes the end of the output and must be called after the last print.
+func (p *recordPrinter) close() error {
+	if p.jsonWriter != nil {
+		return p.jsonWriter.Close()
+	}
+	if err := p.printHeader(); err != nil {
+		return err
+	}
+	p.csvWriter.Flush()
+	return p.csvWriter.Error()
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 
 	"github.com/meyermarcel/icm/configs"
+	"github.com/meyermarcel/icm/cont"
 	"github.com/meyermarcel/icm/data"
 	"github.com/meyermarcel/icm/data/file"
 	"github.com/meyermarcel/icm/http"
@@ -27,6 +28,18 @@ type sizeTypeDecoders struct {
 	lengthDecoder      data.LengthDecoder
 	heightWidthDecoder data.HeightWidthDecoder
 	typeDecoder        data.TypeDecoder
+	// legacySizeTypeDecoder translates size and type codes of the code table of 1984.
+	legacySizeTypeDecoder data.LegacySizeTypeDecoder
+}
+
+func (d decoders) newValidator() *cont.Validator {
+	return cont.NewValidator(
+		d.ownerDecodeUpdater,
+		d.equipCatDecoder,
+		d.lengthDecoder,
+		d.heightWidthDecoder,
+		d.typeDecoder,
+	)
 }
 
 const (
@@ -43,6 +56,8 @@ Edit default configuration for customization:
 
   ` + filepath.Join("$HOME", appDir, configs.ConfigNameWithYmlExt)
 
+var ownerSourcesPath = filepath.Join("$HOME", appDir, configs.OwnerSourcesNameWithYmlExt)
+
 func Execute(version string) {
 	stderr := os.Stderr
 
@@ -67,7 +82,25 @@ func Execute(version string) {
 	checkErr(stderr, err)
 
 	ownerCSVPath := filepath.Join(appDirDataPath, "owner.csv")
-	ownerDecoder, err := file.NewOwnerDecoder(ownerCSVPath, filepath.Join(appDirDataPath, "custom-owner.csv"))
+	checkErr(stderr, file.InitOwnerFile(ownerCSVPath))
+
+	pathToOwnerSources := filepath.Join(appDirPath, configs.OwnerSourcesNameWithYmlExt)
+	if _, err := os.Stat(pathToOwnerSources); os.IsNotExist(err) {
+		errWrite := os.WriteFile(pathToOwnerSources, configs.DefaultOwnerSources(), 0o644)
+		checkErr(stderr, errWrite)
+	}
+
+	ownerSourcesFile, err := os.ReadFile(pathToOwnerSources)
+	checkErr(stderr, err)
+	ownerSources, err := configs.ReadOwnerSources(ownerSourcesFile)
+	checkErr(stderr, err)
+	for i, source := range ownerSources.Sources {
+		if source.Path != "" && !filepath.IsAbs(source.Path) {
+			ownerSources.Sources[i].Path = filepath.Join(appDirPath, source.Path)
+		}
+	}
+
+	ownerDecoder, err := file.NewLayeredOwnerDecoder(ownerSources.Sources, ownerSources.Blocked)
 	checkErr(stderr, err)
 
 	equipCatDecoder, err := file.NewEquipCatDecoder(appDirDataPath)
@@ -79,6 +112,9 @@ func Execute(version string) {
 	typeDecoder, err := file.NewTypeDecoder(appDirDataPath)
 	checkErr(stderr, err)
 
+	legacySizeTypeDecoder, err := file.NewLegacySizeTypeDecoder(appDirDataPath)
+	checkErr(stderr, err)
+
 	downloader := http.NewOwnersDownloader(ownerURL)
 	checkErr(stderr, err)
 
@@ -98,9 +134,11 @@ func Execute(version string) {
 				lengthDecoder,
 				heightWidthDecoder,
 				typeDecoder,
+				legacySizeTypeDecoder,
 			},
 		},
 		file.WriteOwnersCSV,
+		file.ReadOwnersCSV,
 		downloader,
 		timestampUpdater,
 		ownerCSVPath)
@@ -112,7 +150,7 @@ func Execute(version string) {
 	writeErr(stderr, errBuf)
 
 	var errValidate *validateError
-	if errors.As(err, &errValidate) {
+	if errors.As(errCmd, &errValidate) {
 		os.Exit(1)
 	}
 
@@ -125,6 +163,7 @@ func newRootCmd(
 	config *configs.Config,
 	decoders decoders,
 	ownerCreator data.WriteOwnersCSVFunc,
+	ownerReader data.ReadOwnersCSVFunc,
 	ownersDownloader http.OwnersDownloader,
 	timestampUpdater data.TimestampUpdater,
 	ownerCSVPath string,
@@ -147,17 +186,23 @@ Visit github.com/meyermarcel/icm for more docs, issues, pull requests and feedba
 
 	r := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
 
-	rootCmd.AddCommand(newGenerateCmd(writer, writerErr, config, decoders.ownerDecodeUpdater, r))
-	cmd, err := newValidateCmd(os.Stdin, writer, config, decoders)
+	rootCmd.AddCommand(newGenerateCmd(writer, writerErr, config, decoders, ownerCreator, r))
+	cmd, err := newValidateCmd(os.Stdin, writer, writerErr, config, decoders)
 	if err != nil {
 		return nil, err
 	}
 	rootCmd.AddCommand(cmd)
-	downloadOwnersCmd, err := newDownloadOwnersCmd(ownerCreator, timestampUpdater, ownersDownloader, ownerCSVPath)
+	rootCmd.AddCommand(newExtractCmd(os.Stdin, writer, config))
+	rootCmd.AddCommand(newCapacityCmd(writer, config))
+	rootCmd.AddCommand(newExplainCmd(writer))
+	rootCmd.AddCommand(newAnonymizeCmd(os.Stdin, writer, config, decoders.ownerDecodeUpdater))
+	rootCmd.AddCommand(newOwnersCmd(writer, decoders.ownerDecodeUpdater))
+	downloadOwnersCmd, err := newDownloadOwnersCmd(os.Stdin, writer, ownerCreator, ownerReader, timestampUpdater, ownersDownloader, ownerCSVPath)
 	if err != nil {
 		return nil, err
 	}
 	rootCmd.AddCommand(downloadOwnersCmd)
+	rootCmd.AddCommand(newServeCmd(writerErr, decoders, r))
 	rootCmd.AddCommand(newDocCmd(rootCmd))
 
 	return rootCmd, nil
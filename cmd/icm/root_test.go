@@ -21,12 +21,23 @@ func (dummyOwnerDecoder) Decode(code string) (bool, cont.Owner) {
 	}
 }
 
+func (dummyOwnerDecoder) Source(_ string) string {
+	return ""
+}
+
 type dummyOwnerUpdater struct{}
 
 func (dummyOwnerUpdater) GetAllOwnerCodes() []string {
 	return []string{"NAR", "RAN"}
 }
 
+func (dummyOwnerUpdater) GetAllOwners() []cont.Owner {
+	return []cont.Owner{
+		{Code: "NAR", Company: "some-company", City: "some-city", Country: "some-country"},
+		{Code: "RAN", Company: "some-company", City: "some-city", Country: "some-country"},
+	}
+}
+
 func (dummyOwnerUpdater) Update([]cont.Owner) error {
 	panic("implement me")
 }
@@ -50,14 +61,40 @@ func (dummyLengthDecoder) Decode(string) (bool, cont.Length) {
 	return true, "some-length"
 }
 
+func (dummyLengthDecoder) AllLengthCodes() []string {
+	return []string{"2", "4"}
+}
+
 type dummyHeightWidthDecoder struct{}
 
 func (dummyHeightWidthDecoder) Decode(string) (bool, cont.Height, cont.Width) {
 	return true, "some-height", "some-width"
 }
 
+func (dummyHeightWidthDecoder) AllHeightWidthCodes() []string {
+	return []string{"2"}
+}
+
 type dummyTypeDecoder struct{}
 
 func (dummyTypeDecoder) Decode(string) (bool, cont.TypeInfo, cont.GroupInfo) {
 	return true, "some-type", "some-group"
 }
+
+func (dummyTypeDecoder) AllTypeCodes() []string {
+	return []string{"G1", "R1"}
+}
+
+type dummyLegacySizeTypeDecoder struct{}
+
+func (dummyLegacySizeTypeDecoder) DecodeLength(code string) (bool, string) {
+	return code == "4", "4"
+}
+
+func (dummyLegacySizeTypeDecoder) DecodeHeightWidth(code string) (bool, string) {
+	return code == "3", "2"
+}
+
+func (dummyLegacySizeTypeDecoder) DecodeType(code string) (bool, string) {
+	return code == "10", "G1"
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	nethttp "net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/meyermarcel/icm/http"
+	"github.com/spf13/cobra"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+func newServeCmd(writerErr io.Writer, decoders decoders, r *rand.Rand) *cobra.Command {
+	var addr string
+
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve validation and generation over HTTP",
+		Long: `Serve validation, generation and owner lookup over HTTP with JSON.
+
+Following endpoints are available:
+
+  GET  /health
+  POST /validate         {"input": "ABC U 123456 0"}
+  POST /validate/batch   {"inputs": ["ABC U 123456 0", "ABCU1234560 22G1"]}
+  GET  /generate         ?count=&start=&end=&owner=&seed=
+                          &exclude-check-digit-10=&exclude-error-prone-serial-numbers=
+  GET  /owners/{code}
+
+Query parameters of /generate have the same meaning as the flags of the
+generate command. Owners of the owner sources configured in
+
+  ` + ownerSourcesPath + `
+
+are used. No internet connection is required.
+
+The server shuts down gracefully on SIGINT or SIGTERM.`,
+		Example: `icm serve
+icm serve --addr :8080
+curl -s -X POST localhost:8080/validate -d '{"input": "ABC U 123456 0"}'
+curl -s 'localhost:8080/generate?count=3&exclude-check-digit-10=true'`,
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			srv := &nethttp.Server{
+				Addr:              addr,
+				Handler:           http.NewHandler(decoders.newValidator(), decoders.ownerDecodeUpdater, r),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			errCh := make(chan error, 1)
+			go func() {
+				_, _ = fmt.Fprintf(writerErr, "%s: listening on %s\n", appName, addr)
+				errCh <- srv.ListenAndServe()
+			}()
+
+			select {
+			case err := <-errCh:
+				return err
+			case <-ctx.Done():
+			}
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				return err
+			}
+			if err := <-errCh; !errors.Is(err, nethttp.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+	}
+
+	serveCmd.Flags().StringVar(&addr, "addr", "localhost:8080", "TCP address to listen on")
+
+	return serveCmd
+}
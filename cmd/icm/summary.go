@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/meyermarcel/icm/cont"
+	"github.com/meyermarcel/icm/input"
+)
+
+// reasonOther is the reason of invalid lines with errors without code.
+const reasonOther = "OTHER"
+
+// reasonOf returns the code of a cont.ValidateError of err or reasonOther.
+func reasonOf(err error) string {
+	var validateErr *cont.ValidateError
+	if errors.As(err, &validateErr) && validateErr.Code != "" {
+		return string(validateErr.Code)
+	}
+	return reasonOther
+}
+
+// summary has the totals of validated lines.
+type summary struct {
+	Lines           int            `json:"lines"`
+	Valid           int            `json:"valid"`
+	Invalid         int            `json:"invalid"`
+	InvalidByReason map[string]int `json:"invalid-by-reason"`
+	CheckDigit10    int            `json:"check-digit-10"`
+	ErrorProne      int            `json:"error-prone"`
+	UnknownOwners   map[string]int `json:"unknown-owners"`
+	Owners          map[string]int `json:"owners"`
+	Types           map[string]int `json:"types"`
+}
+
+func newSummary() *summary {
+	return &summary{
+		InvalidByReason: map[string]int{},
+		UnknownOwners:   map[string]int{},
+		Owners:          map[string]int{},
+		Types:           map[string]int{},
+	}
+}
+
+// add adds the inputs of a validated line. An invalid line counts for every
+// reason of its inputs. Types are counted as size and type code.
+func (s *summary) add(inputs []input.Input, err error) {
+	s.Lines++
+	if err == nil {
+		s.Valid++
+	} else {
+		s.Invalid++
+	}
+
+	lineReasons := map[string]bool{}
+	// sizeType has the length, height and width and type code if they are valid.
+	sizeType := map[string]string{}
+	for _, in := range inputs {
+		if in.Err() != nil {
+			lineReasons[reasonOf(in.Err())] = true
+			if errors.Is(in.Err(), cont.ErrOwnerNotRegistered) {
+				s.UnknownOwners[in.Value()]++
+			}
+		}
+		for _, datum := range in.Data() {
+			switch datum.Header() {
+			case "calculated-check-digit":
+				if datum.Value() == "10" {
+					s.CheckDigit10++
+				}
+			case "possible-transposition-error":
+				if datum.Value() != "" {
+					s.ErrorProne++
+				}
+			case "owner-code":
+				if in.Err() == nil && datum.Value() != "" {
+					s.Owners[datum.Value()]++
+				}
+			case "length-code", "height-width-code", "type-code":
+				if in.Err() == nil {
+					sizeType[datum.Header()] = datum.Value()
+				}
+			}
+		}
+	}
+	if len(sizeType) == 3 {
+		s.Types[sizeType["length-code"]+sizeType["height-width-code"]+sizeType["type-code"]]++
+	}
+	if err != nil && len(lineReasons) == 0 {
+		lineReasons[reasonOf(err)] = true
+	}
+	for reason := range lineReasons {
+		s.InvalidByReason[reason]++
+	}
+}
+
+// printText writes the summary as human readable text.
+func (s *summary) printText(writer io.Writer) error {
+	_, err := fmt.Fprintf(writer, `Summary
+  lines:          %d
+  valid:          %d
+  invalid:        %d
+  check digit 10: %d
+  error-prone:    %d
+`, s.Lines, s.Valid, s.Invalid, s.CheckDigit10, s.ErrorProne)
+	if err != nil {
+		return err
+	}
+	for _, histogram := range []struct {
+		title  string
+		counts map[string]int
+	}{
+		{"Invalid by reason", s.InvalidByReason},
+		{"Unknown owners", s.UnknownOwners},
+		{"Owners", s.Owners},
+		{"Types", s.Types},
+	} {
+		if len(histogram.counts) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(writer, "%s\n", histogram.title); err != nil {
+			return err
+		}
+		for _, key := range sortedByCount(histogram.counts) {
+			if _, err := fmt.Fprintf(writer, "  %s: %d\n", key, histogram.counts[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// printJSON writes the summary as JSON document with a summary object.
+func (s *summary) printJSON(writer io.Writer) error {
+	b, err := json.Marshal(struct {
+		Summary *summary `json:"summary"`
+	}{s})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(writer, "%s\n", b)
+	return err
+}
+
+// sortedByCount returns the keys of counts sorted by descending count and then by key.
+func sortedByCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if c := cmp.Compare(counts[b], counts[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	return keys
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bufio"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -10,7 +11,6 @@ import (
 	"slices"
 	"strconv"
 	"strings"
-	"unicode/utf8"
 
 	"github.com/logrusorgru/aurora/v4"
 	"github.com/mattn/go-isatty"
@@ -28,23 +28,33 @@ func init() {
 }
 
 type validateError struct {
+	err     error
 	message string
 }
 
-func newValidateError(message string) error {
-	return &validateError{message: message}
+func newValidateError(err error, message string) error {
+	return &validateError{err: err, message: message}
 }
 
 func (e *validateError) Error() string {
 	return e.message
 }
 
+func (e *validateError) Unwrap() error {
+	return e.err
+}
+
 const (
 	auto                   = "auto"
 	containerNumber        = "container-number"
 	owner                  = "owner"
 	ownerEquipmentCategory = "owner-equipment-category"
 	sizeType               = "size-type"
+	// containerNumberSizeType, containerNumberLegacySizeType and legacySizeType
+	// are only names of patterns matched by auto.
+	containerNumberSizeType       = "container-number-size-type"
+	containerNumberLegacySizeType = "container-number-legacy-size-type"
+	legacySizeType                = "legacy-size-type"
 )
 
 const patternsInfo string = `                    ` + auto + ` = matches automatically a pattern
@@ -53,7 +63,44 @@ const patternsInfo string = `                    ` + auto + ` = matches automati
 ` + ownerEquipmentCategory + ` = matches a three letter owner code with equipment category ID
                ` + sizeType + ` = matches length, width+height and type code`
 
-type patterns = [][]func() input.Input
+type pattern struct {
+	name      string
+	newInputs []func() input.Input
+}
+
+type patterns []pattern
+
+func (p patterns) allNewInputs() [][]func() input.Input {
+	var allNewInputs [][]func() input.Input
+	for _, pattern := range p {
+		allNewInputs = append(allNewInputs, pattern.newInputs)
+	}
+	return allNewInputs
+}
+
+const (
+	patternHeader     = "pattern"
+	suggestionsHeader = "suggestions"
+	errorCodesHeader  = "error-codes"
+)
+
+// unionHeaders returns the headers of the data of all patterns in order of first
+// appearance. The header of the pattern column is first.
+func unionHeaders(patterns patterns, suggest bool) []string {
+	headers := []string{patternHeader}
+	for _, p := range patterns {
+		inputs, _ := input.Validate("", p.newInputs)
+		for _, header := range input.Headers(inputs) {
+			if !slices.Contains(headers, header) {
+				headers = append(headers, header)
+			}
+		}
+	}
+	if suggest {
+		headers = append(headers, suggestionsHeader)
+	}
+	return headers
+}
 
 type patternValue struct {
 	config   *configs.Config
@@ -86,6 +133,79 @@ func (*patternValue) Type() string {
 	return "string"
 }
 
+const (
+	matchPerLine   = "per-line"
+	matchFirstLine = "first-line"
+)
+
+const matchesInfo string = `  ` + matchPerLine + ` = matches a pattern for every line
+` + matchFirstLine + ` = matches a pattern for the first line and uses it for all lines`
+
+type matchValue struct {
+	value string
+}
+
+func (m *matchValue) String() string {
+	return m.value
+}
+
+func (m *matchValue) Set(value string) error {
+	switch value {
+	case matchPerLine, matchFirstLine:
+		m.value = value
+		return nil
+	}
+	return fmt.Errorf("%s is not \n%s", value, matchesInfo)
+}
+
+func (*matchValue) Type() string {
+	return "string"
+}
+
+const (
+	failOnAnyInvalid = "any-invalid"
+	failOnAllInvalid = "all-invalid"
+	failOnNever      = "never"
+)
+
+const failOnsInfo string = `` + failOnAnyInvalid + ` = fails if any line is invalid
+` + failOnAllInvalid + ` = fails if all lines are invalid
+      ` + failOnNever + ` = never fails for invalid lines`
+
+type failOnValue struct {
+	value string
+}
+
+func (f *failOnValue) String() string {
+	return f.value
+}
+
+func (f *failOnValue) Set(value string) error {
+	switch value {
+	case failOnAnyInvalid, failOnAllInvalid, failOnNever:
+		f.value = value
+		return nil
+	}
+	return fmt.Errorf("%s is not \n%s", value, failOnsInfo)
+}
+
+func (*failOnValue) Type() string {
+	return "string"
+}
+
+// err returns the first validation error of the lines if the lines fail.
+func (f *failOnValue) err(firstErr error, lines, invalid int) error {
+	switch f.value {
+	case failOnNever:
+		return nil
+	case failOnAllInvalid:
+		if lines == 0 || invalid < lines {
+			return nil
+		}
+	}
+	return firstErr
+}
+
 func (p *patternValue) getPatterns(value string) patterns {
 	switch value {
 
@@ -105,9 +225,11 @@ func (p *patternValue) getPatterns(value string) patterns {
 }
 
 const (
-	outputAuto  = "auto"
-	outputFancy = "fancy"
-	outputCSV   = "csv"
+	outputAuto   = "auto"
+	outputFancy  = "fancy"
+	outputCSV    = "csv"
+	outputJSON   = "json"
+	outputNDJSON = "ndjson"
 )
 
 type outputValue struct {
@@ -127,7 +249,7 @@ func (o *outputValue) String() string {
 
 func (o *outputValue) Set(value string) error {
 	switch value {
-	case outputAuto, outputFancy, outputCSV:
+	case outputAuto, outputFancy, outputCSV, outputJSON, outputNDJSON:
 		o.value = value
 		return nil
 	}
@@ -138,32 +260,48 @@ func (o *outputValue) Type() string {
 	return "string"
 }
 
-const outputsInfo string = ` ` + outputAuto + ` = for a single line '` + outputFancy +
+const outputsInfo string = `  ` + outputAuto + ` = for a single line '` + outputFancy +
 	`' and for multiple lines '` + outputCSV + `' output 
-  ` + outputCSV + ` = machine readable CSV output
-` + outputFancy + ` = human readable fancy output`
-
-func (o *outputValue) getPrinter(value string, writer io.Writer, isSingleLine bool) input.Printer {
+   ` + outputCSV + ` = machine readable CSV output
+  ` + outputJSON + ` = machine readable JSON document with an object per line
+` + outputNDJSON + ` = machine readable JSON object per line (newline delimited JSON)
+ ` + outputFancy + ` = human readable fancy output`
+
+// getPrinter returns the printer for value. CSV and JSON printers use headers
+// as fixed headers if headers is not nil.
+func (o *outputValue) getPrinter(value string, writer io.Writer, isSingleLine bool, headers []string) input.Printer {
 	switch value {
 	case outputFancy:
 		return newFancyPrinter(writer, o.config)
 	case outputCSV:
-		return newCSVPrinter(writer, o.config)
+		return newCSVPrinter(writer, o.config, headers)
+	case outputJSON:
+		return newJSONPrinter(input.NewJSONPrinter(writer), headers)
+	case outputNDJSON:
+		return newJSONPrinter(input.NewNDJSONPrinter(writer), headers)
 	case outputAuto:
 		fallthrough
 	default:
 		if isSingleLine {
 			return newFancyPrinter(writer, o.config)
 		}
-		return newCSVPrinter(writer, o.config)
+		return newCSVPrinter(writer, o.config, headers)
 	}
 }
 
-func newValidateCmd(stdin io.Reader, writer io.Writer, config *configs.Config, decoders decoders) (*cobra.Command, error) {
+func newValidateCmd(stdin io.Reader, writer, writerErr io.Writer, config *configs.Config, decoders decoders) (*cobra.Command, error) {
 	pValue := newPatternValue(config, decoders)
 
 	oValue := newOutputValue(config)
 
+	mValue := &matchValue{value: configs.DefaultValues.Match}
+
+	var suggest bool
+
+	var printSummary bool
+
+	fValue := &failOnValue{value: failOnAnyInvalid}
+
 	validateCmd := &cobra.Command{
 		Use:   "validate",
 		Short: "Validate intermodal container markings",
@@ -175,6 +313,49 @@ For multi line input CSV output is used. For example this is useful to scan
 data sets for error-prone serial numbers. It is also possible to generate
 CSV data sets of random container numbers.
 
+JSON output is available as a single document or as newline delimited JSON
+with one object per line. Every object has an errors array with an object for
+every error of the line with code, part, value, expected value and message.
+
+CSV output has an error-codes column with the codes of all errors of the line.
+Codes are stable (e.g. OWNER_NOT_REGISTERED or CHECK_DIGIT_MISMATCH). Unlike
+error messages, codes do not change and can be used to handle errors without
+parsing the messages.
+
+With pattern 'auto' a pattern is matched for every line. Mixed input of owner
+codes, container numbers and size type codes is validated line by line. CSV
+and JSON output have a pattern column with the name of the matched pattern
+and a header with the columns of all patterns. With --match first-line the
+pattern of the first line is used for all lines.
+
+Size and type codes of the code table of 1984 with digits only (e.g. 2210 or
+4310) are recognised by pattern 'auto' as legacy codes. Legacy codes are
+translated to current codes (e.g. 22G1 or 42G1) and validated as current
+codes. CSV and JSON output have the legacy code and the current code.
+
+With --suggest the most likely intended container numbers are proposed for
+invalid container numbers. Suggestions are found by substituting a single
+character, by transposing adjacent characters and by correcting characters
+that are often confused (O/0, I/1, B/8, S/5 and Z/2). Only suggestions with
+a registered owner and a valid check digit are proposed.
+
+With --summary totals of all lines are printed after validation: valid and
+invalid lines, invalid lines by error code, check digits 10, error-prone serial
+numbers, unknown owners and histograms of owners and types. The summary is
+printed to stderr or for JSON output as a trailing JSON document.
+
+Owners are looked up in the owner sources configured in
+
+  ` + ownerSourcesPath + `
+
+The source of an owner is printed (e.g. owner from custom-owner.csv) and is
+the owner-source column of CSV and JSON output. Blocked owner codes, e.g.
+retired ones, are invalid.
+
+The exit code is 1 if any line is invalid. With --fail-on all-invalid the
+exit code is 1 only if all lines are invalid and with --fail-on never
+invalid lines never change the exit code.
+
 ` + sepHelp,
 		Example: `icm validate ABC
 # Validate with pattern 'container-number' instead of pattern 'auto'
@@ -186,14 +367,27 @@ icm validate --sep-owner-equip '' --sep-serial-check '-' ABC U 123456 0
 icm validate 20G1
 # Validate a container number with a type
 icm validate ABC U 123456 0 20G1
+# Validate a legacy size and type code of 1984 and show the current code
+icm validate 4310
 # Validate a random container number
 icm generate | icm validate
 icm generate --count 10 | icm validate
 icm generate --count 10 | icm validate --output fancy
 # Generate CSV data set
 icm generate --count 1000000 | icm validate
+# Validate mixed input line by line
+printf 'ABC\nABC U 123456 0\n22G1\n' | icm validate
+# Validate all lines with the pattern of the first line
+printf 'ABC\nABC U 123456 0\n' | icm validate --match first-line
+# Validate with JSON output
+icm generate --count 10 | icm validate --output json
+icm generate --count 10 | icm validate --output ndjson
 # Validate a container number with 6 (!) error-prone serial numbers combinations
-icm validate APL U 689473 0`,
+icm validate APL U 689473 0
+# Validate a mis-read container number and propose corrections
+icm validate --suggest ABC U 12345B 0
+# Validate a data set, print totals and fail only if all lines are invalid
+icm generate --count 1000 | icm validate --summary --fail-on all-invalid > /dev/null`,
 		Args:              cobra.MaximumNArgs(6),
 		ValidArgsFunction: cobra.NoFileCompletions,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -210,25 +404,55 @@ icm validate APL U 689473 0`,
 			peek, _ := bufReader.Peek(bufReader.Size())
 			singleLine := isSingleLine(string(peek))
 
-			printer := oValue.getPrinter(config.Output(), writer, singleLine)
-
 			patterns := pValue.getPatterns(config.Pattern())
 
-			newInputs := input.Match(strings.Split(string(peek), "\n")[0], patterns)
+			perLine := config.Pattern() == auto && config.Match() == matchPerLine
+
+			var headers []string
+			if perLine {
+				headers = unionHeaders(patterns, suggest)
+			}
 
-			scanner := bufio.NewScanner(bufReader)
+			printer := oValue.getPrinter(config.Output(), writer, singleLine, headers)
+			_, isCSV := printer.(*input.CSVPrinter)
 
-			var inputErr error
-			var inputs []input.Input
+			validate := newLineValidator(patterns, strings.Split(string(peek), "\n")[0], perLine, suggest, isCSV,
+				decoders.newValidator(), config)
 
-			for scanner.Scan() {
-				inputs, inputErr = input.Validate(scanner.Text(), newInputs)
-				err := printer.Print(inputs)
+			lineSummary := newSummary()
+			var firstErr error
+			pipeline := input.NewPipeline(validate, printer)
+			pipeline.SetResultFunc(func(inputs []input.Input, err error) {
+				lineSummary.add(inputs, err)
+				if firstErr == nil {
+					firstErr = err
+				}
+			})
+
+			if err := pipeline.Run(bufReader); err != nil {
+				return err
+			}
+
+			if closer, ok := printer.(io.Closer); ok {
+				if err := closer.Close(); err != nil {
+					return err
+				}
+			}
+
+			if printSummary {
+				var err error
+				switch config.Output() {
+				case outputJSON, outputNDJSON:
+					err = lineSummary.printJSON(writer)
+				default:
+					err = lineSummary.printText(writerErr)
+				}
 				if err != nil {
 					return err
 				}
 			}
-			return inputErr
+
+			return fValue.err(firstErr, lineSummary.Lines, lineSummary.Invalid)
 		},
 	}
 
@@ -244,12 +468,36 @@ icm validate APL U 689473 0`,
 	if err != nil {
 		return nil, err
 	}
+	validateCmd.Flags().Var(mValue, configs.FlagNames.Match,
+		fmt.Sprintf("sets matching of pattern %s to %s or %s\n%s\n",
+			auto, matchPerLine, matchFirstLine,
+			matchesInfo))
+	err = validateCmd.RegisterFlagCompletionFunc(configs.FlagNames.Match, func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{matchPerLine, matchFirstLine}, cobra.ShellCompDirectiveNoFileComp
+	})
+	if err != nil {
+		return nil, err
+	}
 	validateCmd.Flags().Var(oValue, configs.FlagNames.Output,
-		fmt.Sprintf("sets output to %s, %s or %s\n%s\n",
-			outputAuto, outputFancy, outputCSV,
+		fmt.Sprintf("sets output to %s, %s, %s, %s or %s\n%s\n",
+			outputAuto, outputFancy, outputCSV, outputJSON, outputNDJSON,
 			outputsInfo))
 	err = validateCmd.RegisterFlagCompletionFunc(configs.FlagNames.Output, func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
-		return []string{outputAuto, outputFancy, outputCSV}, cobra.ShellCompDirectiveNoFileComp
+		return []string{outputAuto, outputFancy, outputCSV, outputJSON, outputNDJSON}, cobra.ShellCompDirectiveNoFileComp
+	})
+	if err != nil {
+		return nil, err
+	}
+	validateCmd.Flags().BoolVar(&suggest, "suggest", false,
+		"proposes the most likely intended container numbers for invalid container numbers")
+	validateCmd.Flags().BoolVar(&printSummary, "summary", false,
+		"prints totals of validated lines to stderr or as trailing JSON document for JSON output")
+	validateCmd.Flags().Var(fValue, "fail-on",
+		fmt.Sprintf("sets exit code policy for invalid lines to %s, %s or %s\n%s\n",
+			failOnAnyInvalid, failOnAllInvalid, failOnNever,
+			failOnsInfo))
+	err = validateCmd.RegisterFlagCompletionFunc("fail-on", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{failOnAnyInvalid, failOnAllInvalid, failOnNever}, cobra.ShellCompDirectiveNoFileComp
 	})
 	if err != nil {
 		return nil, err
@@ -269,6 +517,64 @@ icm validate APL U 689473 0`,
 	return validateCmd, nil
 }
 
+// newLineValidator returns a function that validates a line with the pattern matched
+// for the line if perLine is true. Otherwise, the pattern matched for firstLine is used.
+// If addErrorCodes is true, the codes of the errors are added as last data.
+func newLineValidator(
+	patterns patterns,
+	firstLine string,
+	perLine, suggest, addErrorCodes bool,
+	validator *cont.Validator,
+	config *configs.Config,
+) func(line string) ([]input.Input, error) {
+	allNewInputs := patterns.allNewInputs()
+	firstLinePattern := patterns[input.MatchIndex(firstLine, allNewInputs)]
+
+	return func(line string) ([]input.Input, error) {
+		pattern := firstLinePattern
+		if perLine {
+			pattern = patterns[input.MatchIndex(line, allNewInputs)]
+		}
+		inputs, err := input.Validate(line, pattern.newInputs)
+		if perLine {
+			inputs[0].AddData(input.NewDatum(patternHeader).WithValue(pattern.name))
+		}
+		if suggest {
+			addSuggestions(inputs, line, validator, config)
+		}
+		if addErrorCodes {
+			inputs[len(inputs)-1].AddData(input.NewDatum(errorCodesHeader).WithValues(errorCodes(inputs)))
+		}
+		return inputs, err
+	}
+}
+
+// errorCodes returns the codes of the errors of inputs in order of the inputs.
+func errorCodes(inputs []input.Input) []string {
+	codes := []string{}
+	for _, in := range inputs {
+		var validateErr *cont.ValidateError
+		if errors.As(in.Err(), &validateErr) && validateErr.Code != "" {
+			codes = append(codes, string(validateErr.Code))
+		}
+	}
+	return codes
+}
+
+// jsonError returns the code, the part and the values of a cont.ValidateError of err.
+func jsonError(err error) input.JSONError {
+	var validateErr *cont.ValidateError
+	if !errors.As(err, &validateErr) {
+		return input.JSONError{}
+	}
+	return input.JSONError{
+		Code:     string(validateErr.Code),
+		Part:     validateErr.Part,
+		Value:    validateErr.Value,
+		Expected: validateErr.Expected,
+	}
+}
+
 func isSingleLine(s string) bool {
 	scanner := bufio.NewScanner(strings.NewReader(s))
 	counter := 0
@@ -305,60 +611,83 @@ func newFancyPrinter(writer io.Writer, config *configs.Config) input.Printer {
 	return fancyPrinter
 }
 
-func newCSVPrinter(writer io.Writer, config *configs.Config) input.Printer {
+func newCSVPrinter(writer io.Writer, config *configs.Config, headers []string) input.Printer {
 	csvWriter := csv.NewWriter(writer)
 	csvWriter.Comma = ';'
-	return input.NewCSVPrinter(csvWriter, config.NoHeader())
+	csvPrinter := input.NewCSVPrinter(csvWriter, config.NoHeader())
+	if headers != nil {
+		csvPrinter.SetHeaders(append(slices.Clone(headers), errorCodesHeader))
+	}
+	return csvPrinter
 }
 
-func newAutoPattern(config *configs.Config, decoders decoders) patterns {
-	owner := newOwnerInput(decoders.ownerDecodeUpdater)
-	equipCat := newEquipCatInput(decoders.equipCatDecoder)
-	serialNum := newSerialNumInput()
-	checkDigit := newCheckDigitInput(config)
-	length := newLengthInput(decoders.lengthDecoder)
-	heightWidth := newHeightWidthInput(decoders.heightWidthDecoder)
-	typeAndGroup := newTypeAndGroupInput(decoders.typeDecoder)
+func newJSONPrinter(jsonPrinter *input.JSONPrinter, headers []string) input.Printer {
+	if headers != nil {
+		jsonPrinter.SetHeaders(headers)
+	}
+	jsonPrinter.SetErrorFunc(jsonError)
+	return jsonPrinter
+}
 
+func newAutoPattern(config *configs.Config, decoders decoders) patterns {
+	validator := decoders.newValidator()
+	ownerCode := newOwnerInput(validator, decoders.ownerDecodeUpdater)
+	equipCat := newEquipCatInput(validator, decoders.equipCatDecoder)
+	serialNum := newSerialNumInput(validator)
+	checkDigit := newCheckDigitInput(validator, config)
+	length := newLengthInput(validator)
+	heightWidth := newHeightWidthInput(validator)
+	typeAndGroup := newTypeAndGroupInput(validator)
+	legacyLength := newLegacyLengthInput(validator, decoders.legacySizeTypeDecoder)
+	legacyHeightWidth := newLegacyHeightWidthInput(validator, decoders.legacySizeTypeDecoder)
+	legacyTypeAndGroup := newLegacyTypeAndGroupInput(validator, decoders.legacySizeTypeDecoder)
+
+	// Legacy codes have digits only and would also match as current codes.
+	// Therefore, patterns with legacy codes are matched first.
 	return patterns{
-		{owner, equipCat, serialNum, checkDigit, length, heightWidth, typeAndGroup},
-		{owner, equipCat, serialNum, checkDigit},
-		{owner, equipCat},
-		{owner},
-		{length, heightWidth, typeAndGroup},
+		{containerNumberLegacySizeType, []func() input.Input{ownerCode, equipCat, serialNum, checkDigit, legacyLength, legacyHeightWidth, legacyTypeAndGroup}},
+		{containerNumberSizeType, []func() input.Input{ownerCode, equipCat, serialNum, checkDigit, length, heightWidth, typeAndGroup}},
+		{containerNumber, []func() input.Input{ownerCode, equipCat, serialNum, checkDigit}},
+		{ownerEquipmentCategory, []func() input.Input{ownerCode, equipCat}},
+		{owner, []func() input.Input{ownerCode}},
+		{legacySizeType, []func() input.Input{legacyLength, legacyHeightWidth, legacyTypeAndGroup}},
+		{sizeType, []func() input.Input{length, heightWidth, typeAndGroup}},
 	}
 }
 
 func newContNumPattern(config *configs.Config, decoders decoders) patterns {
-	owner := newOwnerInput(decoders.ownerDecodeUpdater)
-	equipCat := newEquipCatInput(decoders.equipCatDecoder)
-	serialNum := newSerialNumInput()
-	checkDigit := newCheckDigitInput(config)
+	validator := decoders.newValidator()
+	ownerCode := newOwnerInput(validator, decoders.ownerDecodeUpdater)
+	equipCat := newEquipCatInput(validator, decoders.equipCatDecoder)
+	serialNum := newSerialNumInput(validator)
+	checkDigit := newCheckDigitInput(validator, config)
 
-	return patterns{{owner, equipCat, serialNum, checkDigit}}
+	return patterns{{containerNumber, []func() input.Input{ownerCode, equipCat, serialNum, checkDigit}}}
 }
 
 func newOwnerPattern(decoders decoders) patterns {
-	owner := newOwnerInput(decoders.ownerDecodeUpdater)
-	return patterns{{owner}}
+	ownerCode := newOwnerInput(decoders.newValidator(), decoders.ownerDecodeUpdater)
+	return patterns{{owner, []func() input.Input{ownerCode}}}
 }
 
 func newOwnerEquipCatPattern(decoders decoders) patterns {
-	owner := newOwnerInput(decoders.ownerDecodeUpdater)
-	equipCat := newEquipCatInput(decoders.equipCatDecoder)
+	validator := decoders.newValidator()
+	ownerCode := newOwnerInput(validator, decoders.ownerDecodeUpdater)
+	equipCat := newEquipCatInput(validator, decoders.equipCatDecoder)
 
-	return patterns{{owner, equipCat}}
+	return patterns{{ownerEquipmentCategory, []func() input.Input{ownerCode, equipCat}}}
 }
 
 func newSizeTypePattern(decoders decoders) patterns {
-	length := newLengthInput(decoders.lengthDecoder)
-	heightWidth := newHeightWidthInput(decoders.heightWidthDecoder)
-	typeAndGroup := newTypeAndGroupInput(decoders.typeDecoder)
+	validator := decoders.newValidator()
+	length := newLengthInput(validator)
+	heightWidth := newHeightWidthInput(validator)
+	typeAndGroup := newTypeAndGroupInput(validator)
 
-	return patterns{{length, heightWidth, typeAndGroup}}
+	return patterns{{sizeType, []func() input.Input{length, heightWidth, typeAndGroup}}}
 }
 
-func newOwnerInput(ownerDecoder data.OwnerDecoder) func() input.Input {
+func newOwnerInput(validator *cont.Validator, ownerDecoder data.OwnerDecoder) func() input.Input {
 	owner := input.NewInput(
 		3,
 		regexp.MustCompile(`[A-Za-z]{3}`).FindStringIndex,
@@ -367,67 +696,74 @@ func newOwnerInput(ownerDecoder data.OwnerDecoder) func() input.Input {
 			ownerCompanyDatum := input.NewDatum("company")
 			ownerCityDatum := input.NewDatum("city")
 			ownerCountryDatum := input.NewDatum("country")
+			ownerSourceDatum := input.NewDatum("owner-source")
 
-			if value == "" {
-				return newValidateError(fmt.Sprintf("%s is not %s long (e.g. %s)",
+			result := validator.ValidateOwner(value)
+			switch {
+			case errors.Is(result.Err, cont.ErrOwnerCodeFormat):
+				return newValidateError(result.Err, fmt.Sprintf("%s is not %s long (e.g. %s)",
 						au.Underline("owner code"),
 						au.Bold("3 letters"),
 						au.Underline(ownerDecoder.GetAllOwnerCodes()[0]))),
 					nil,
-					[]input.Datum{ownerCodeDatum, ownerCompanyDatum, ownerCityDatum, ownerCountryDatum}
-			}
-			found, owner := ownerDecoder.Decode(value)
-			if !found {
-				return newValidateError(fmt.Sprintf("%s is not %s (e.g. %s)",
+					[]input.Datum{ownerCodeDatum, ownerCompanyDatum, ownerCityDatum, ownerCountryDatum, ownerSourceDatum}
+			case errors.Is(result.Err, cont.ErrOwnerNotRegistered):
+				return newValidateError(result.Err, fmt.Sprintf("%s is not %s (e.g. %s)",
 						au.Underline(value),
 						au.Bold("registered"),
 						au.Underline(ownerDecoder.GetAllOwnerCodes()[0]))),
 					nil,
-					[]input.Datum{ownerCodeDatum, ownerCompanyDatum, ownerCityDatum, ownerCountryDatum}
+					[]input.Datum{ownerCodeDatum, ownerCompanyDatum, ownerCityDatum, ownerCountryDatum, ownerSourceDatum}
+			case errors.Is(result.Err, cont.ErrOwnerBlocked):
+				return newValidateError(result.Err, fmt.Sprintf("%s is %s (%s)",
+						au.Underline(value),
+						au.Bold("blocked"),
+						result.BlockedReason)),
+					nil,
+					[]input.Datum{ownerCodeDatum, ownerCompanyDatum, ownerCityDatum, ownerCountryDatum, ownerSourceDatum}
+			}
+			lines := []string{
+				result.Owner.Company,
+				result.Owner.City,
+				result.Owner.Country,
+			}
+			if source := ownerDecoder.Source(result.Owner.Code); source != "" {
+				lines = append(lines, fmt.Sprintf("owner from %s", source))
+				ownerSourceDatum = ownerSourceDatum.WithValue(source)
 			}
 			return nil,
-				[]string{
-					owner.Company,
-					owner.City,
-					owner.Country,
-				},
+				lines,
 				[]input.Datum{
-					ownerCodeDatum.WithValue(owner.Code),
-					ownerCompanyDatum.WithValue(owner.Company),
-					ownerCityDatum.WithValue(owner.City),
-					ownerCountryDatum.WithValue(owner.Country),
+					ownerCodeDatum.WithValue(result.Owner.Code),
+					ownerCompanyDatum.WithValue(result.Owner.Company),
+					ownerCityDatum.WithValue(result.Owner.City),
+					ownerCountryDatum.WithValue(result.Owner.Country),
+					ownerSourceDatum,
 				}
 		})
 	owner.SetToUpper()
 	return func() input.Input { return owner }
 }
 
-func newEquipCatInput(equipCatDecoder data.EquipCatDecoder) func() input.Input {
+func newEquipCatInput(validator *cont.Validator, equipCatDecoder data.EquipCatDecoder) func() input.Input {
 	equipCat := input.NewInput(
 		1,
 		regexp.MustCompile(`[A-Za-z]`).FindStringIndex,
 		func(value string, _ []string) (error, []string, []input.Datum) {
 			equipCatIDDatum := input.NewDatum("equipment-category-id").WithValue(value)
 			equipCatDatum := input.NewDatum("equipment-category")
-			if value == "" {
-				return newValidateError(fmt.Sprintf("%s is not %s",
-						au.Underline("equipment category id"),
-						equipCatIDsAsList(equipCatDecoder))),
-					nil,
-					[]input.Datum{equipCatIDDatum, equipCatDatum}
-			}
 
-			found, cat := equipCatDecoder.Decode(value)
-			if !found {
-				return newValidateError(fmt.Sprintf("%s is not %s",
+			result := validator.ValidateEquipCat(value)
+			if result.Err != nil {
+				return newValidateError(result.Err, fmt.Sprintf("%s is not %s",
 						au.Underline("equipment category id"),
 						equipCatIDsAsList(equipCatDecoder))),
 					nil,
 					[]input.Datum{equipCatIDDatum, equipCatDatum}
 			}
 			return nil,
-				[]string{cat.Info},
-				[]input.Datum{equipCatIDDatum, equipCatDatum.WithValue(cat.Info)}
+				[]string{result.EquipCat.Info},
+				[]input.Datum{equipCatIDDatum, equipCatDatum.WithValue(result.EquipCat.Info)}
 		})
 	equipCat.SetToUpper()
 	return func() input.Input { return equipCat }
@@ -450,15 +786,18 @@ func equipCatIDsAsList(equipCatDecoder data.EquipCatDecoder) string {
 	return b.String()
 }
 
-func newSerialNumInput() func() input.Input {
+func newSerialNumInput(validator *cont.Validator) func() input.Input {
+	serialNumMatchIndex := regexp.MustCompile(`\d{6}`).FindStringIndex
 	return func() input.Input {
 		return input.NewInput(
 			6,
-			regexp.MustCompile(`\d{6}`).FindStringIndex,
+			serialNumMatchIndex,
 			func(value string, _ []string) (error, []string, []input.Datum) {
 				serialNumData := input.NewDatum("serial-number")
-				if value == "" {
-					return newValidateError(fmt.Sprintf("%s is not %s long",
+
+				result := validator.ValidateSerialNum(value)
+				if result.Err != nil {
+					return newValidateError(result.Err, fmt.Sprintf("%s is not %s long",
 							au.Underline("serial number"),
 							au.Bold("6 numbers"))),
 						nil,
@@ -469,32 +808,36 @@ func newSerialNumInput() func() input.Input {
 	}
 }
 
-func newCheckDigitInput(config *configs.Config) func() input.Input {
+func newCheckDigitInput(validator *cont.Validator, config *configs.Config) func() input.Input {
+	checkDigitMatchIndex := regexp.MustCompile(`\d`).FindStringIndex
 	return func() input.Input {
 		return input.NewInput(
 			1,
-			regexp.MustCompile(`\d`).FindStringIndex,
+			checkDigitMatchIndex,
 			func(value string, previousValues []string) (error, []string, []input.Datum) {
-				checkDigitDatum := input.NewDatum("check-digit").WithValue(value)
+				checkDigitDatum := input.NewDatum("check-digit")
+				if n, err := strconv.Atoi(value); err == nil {
+					checkDigitDatum = checkDigitDatum.WithInt(n)
+				}
 				calcCheckDigitDatum := input.NewDatum("calculated-check-digit")
 				validCheckDigit := input.NewDatum("valid-check-digit")
-				errorProneSerialNumbers := input.NewDatum("possible-transposition-error")
-				if len(strings.Join(previousValues[0:3], "")) != 10 {
-					return newValidateError(fmt.Sprintf("%s is not calculable",
+				errorProneSerialNumbers := input.NewListDatum("possible-transposition-error")
+
+				result := validator.ValidateCheckDigit(previousValues[2], previousValues[1], previousValues[0], value)
+				checkDigit := result.CalcCheckDigit
+
+				if errors.Is(result.Err, cont.ErrCheckDigitNotCalculable) {
+					return newValidateError(result.Err, fmt.Sprintf("%s is not calculable",
 							au.Underline("check digit"))),
 						nil,
 						[]input.Datum{
 							checkDigitDatum,
 							calcCheckDigitDatum,
-							validCheckDigit.WithValue(fmt.Sprintf("%t", false)),
+							validCheckDigit.WithBool(false),
 							errorProneSerialNumbers,
 						}
 				}
 
-				equipCatID, _ := utf8.DecodeRuneInString(previousValues[1])
-				serialNum, _ := strconv.Atoi(previousValues[0])
-				checkDigit := cont.CalcCheckDigit(previousValues[2], equipCatID, serialNum)
-
 				var lines []string
 				if checkDigit == 10 {
 					lines = append(
@@ -504,42 +847,39 @@ func newCheckDigitInput(config *configs.Config) func() input.Input {
 					)
 				}
 
-				number, err := strconv.Atoi(value)
-				if err != nil {
-					return newValidateError(fmt.Sprintf("%s must be a %s (calculated: %s)",
+				if errors.Is(result.Err, cont.ErrCheckDigitFormat) {
+					return newValidateError(result.Err, fmt.Sprintf("%s must be a %s (calculated: %s)",
 							au.Underline("check digit"),
 							au.Bold("number"),
 							au.Green(strconv.Itoa(checkDigit)))),
 						lines,
 						[]input.Datum{
 							checkDigitDatum,
-							calcCheckDigitDatum.WithValue(strconv.Itoa(checkDigit)),
-							validCheckDigit.WithValue(fmt.Sprintf("%t", false)),
+							calcCheckDigitDatum.WithInt(checkDigit),
+							validCheckDigit.WithBool(false),
 							errorProneSerialNumbers,
 						}
 				}
 
-				if number != checkDigit%10 {
-					return newValidateError(fmt.Sprintf(
+				if errors.Is(result.Err, cont.ErrCheckDigitMismatch) {
+					return newValidateError(result.Err, fmt.Sprintf(
 							"calculated %s is %s",
 							au.Underline("check digit"),
 							au.Green(strconv.Itoa(checkDigit%10)))),
 						lines,
 						[]input.Datum{
 							checkDigitDatum,
-							calcCheckDigitDatum.WithValue(strconv.Itoa(checkDigit)),
-							validCheckDigit.WithValue(fmt.Sprintf("%t", number == checkDigit%10)),
+							calcCheckDigitDatum.WithInt(checkDigit),
+							validCheckDigit.WithBool(false),
 							errorProneSerialNumbers,
 						}
 				}
 
-				transposedContNums := cont.CheckTransposition(previousValues[2], equipCatID, serialNum, checkDigit)
-
-				if transposedContNums != nil {
+				if result.ErrorProneNumbers != nil {
 					lines = append(lines, "Error-prone serial numbers:")
-					builder := strings.Builder{}
+					var errorProneContNums []string
 
-					for idx, tcn := range transposedContNums {
+					for _, tcn := range result.ErrorProneNumbers {
 
 						serialNumber := fmt.Sprintf("%06d", tcn.SerialNumber)
 						serialNumberFmt := ""
@@ -570,18 +910,19 @@ func newCheckDigitInput(config *configs.Config) func() input.Input {
 							serialNumberFmt, config.SepSC(),
 							digitFmt)
 						lines = append(lines, fmt.Sprintf("  %s", contNumFmt))
-						builder.WriteString(contNumFmt)
-						if idx < len(transposedContNums)-1 {
-							builder.WriteString(", ")
-						}
+						errorProneContNums = append(errorProneContNums, fmt.Sprintf("%s%s%s%s%06d%s%d",
+							tcn.OwnerCode, config.SepOE(),
+							string(tcn.EquipCatID), config.SepES(),
+							tcn.SerialNumber, config.SepSC(),
+							tcn.CheckDigit))
 					}
 					return nil,
 						lines,
 						[]input.Datum{
 							checkDigitDatum,
-							calcCheckDigitDatum.WithValue(strconv.Itoa(checkDigit)),
-							validCheckDigit.WithValue(fmt.Sprintf("%t", number == checkDigit%10)),
-							errorProneSerialNumbers.WithValue(builder.String()),
+							calcCheckDigitDatum.WithInt(checkDigit),
+							validCheckDigit.WithBool(true),
+							errorProneSerialNumbers.WithValues(errorProneContNums),
 						}
 				}
 
@@ -589,122 +930,286 @@ func newCheckDigitInput(config *configs.Config) func() input.Input {
 					lines,
 					[]input.Datum{
 						checkDigitDatum,
-						calcCheckDigitDatum.WithValue(strconv.Itoa(checkDigit)),
-						validCheckDigit.WithValue(fmt.Sprintf("%t", number == checkDigit%10)),
+						calcCheckDigitDatum.WithInt(checkDigit),
+						validCheckDigit.WithBool(true),
 						errorProneSerialNumbers,
 					}
 			})
 	}
 }
 
-func newLengthInput(lengthDecoder data.LengthDecoder) func() input.Input {
+const maxSuggestions = 5
+
+// addSuggestions adds suggestions for the line to the last input. A mis-read container
+// number often matches a shorter pattern, therefore all patterns get suggestions.
+func addSuggestions(inputs []input.Input, line string, validator *cont.Validator, config *configs.Config) {
+	last := &inputs[len(inputs)-1]
+	suggestionsDatum := input.NewListDatum(suggestionsHeader)
+
+	suggestions := validator.Suggest(line)
+	if suggestions == nil {
+		last.AddData(suggestionsDatum)
+		return
+	}
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	lines := []string{"Did you mean:"}
+	var contNums []string
+	for _, s := range suggestions {
+		edited := map[int]bool{}
+		for _, e := range s.Edits {
+			edited[e.Pos] = true
+			if e.Kind == cont.EditTransposition {
+				edited[e.Pos+1] = true
+			}
+		}
+		fmtChars := func(chars string, offset int) string {
+			b := strings.Builder{}
+			for i := 0; i < len(chars); i++ {
+				if edited[offset+i] {
+					b.WriteString(fmt.Sprintf("%c", au.Magenta(chars[i])))
+				} else {
+					b.WriteByte(chars[i])
+				}
+			}
+			return b.String()
+		}
+
+		n := s.Number
+		serialNumber := fmt.Sprintf("%06d", n.SerialNumber)
+		lines = append(lines, fmt.Sprintf("  %s%s%s%s%s%s%s",
+			fmtChars(n.OwnerCode, 0), config.SepOE(),
+			fmtChars(string(n.EquipCatID), 3), config.SepES(),
+			fmtChars(serialNumber, 4), config.SepSC(),
+			fmtChars(strconv.Itoa(n.CheckDigit), 10)))
+		contNums = append(contNums, fmt.Sprintf("%s%s%s%s%s%s%d",
+			n.OwnerCode, config.SepOE(),
+			string(n.EquipCatID), config.SepES(),
+			serialNumber, config.SepSC(),
+			n.CheckDigit))
+	}
+	last.AddLines(lines...)
+	last.AddData(suggestionsDatum.WithValues(contNums))
+}
+
+func newLengthInput(validator *cont.Validator) func() input.Input {
 	length := input.NewInput(
 		1,
 		regexp.MustCompile(`[A-Za-z\d]`).FindStringIndex,
 		func(value string, _ []string) (error, []string, []input.Datum) {
-			lengthDatum := input.NewDatum("length-code").WithValue(value)
-			lengthDescDatum := input.NewDatum("length-description")
-			if value == "" {
-				return newValidateError(fmt.Sprintf("%s is not a %s or a %s",
-						au.Underline("length code"),
-						au.Bold("valid number"),
-						au.Bold("valid character"))),
-					nil,
-					[]input.Datum{lengthDatum, lengthDescDatum}
-			}
-
-			found, length := lengthDecoder.Decode(value)
-			if !found {
-				return newValidateError(fmt.Sprintf("%s is not %s",
-						au.Underline("length code"),
-						au.Bold("valid"))),
-					nil,
-					[]input.Datum{lengthDatum, lengthDescDatum}
-			}
-			return nil,
-				[]string{fmt.Sprintf("length: %s", length)},
-				[]input.Datum{lengthDatum, lengthDescDatum.WithValue(string(length))}
+			return validateLength(validator, value)
 		})
 	length.SetToUpper()
 	return func() input.Input { return length }
 }
 
-func newHeightWidthInput(heightWidthDecoder data.HeightWidthDecoder) func() input.Input {
+func validateLength(validator *cont.Validator, value string) (error, []string, []input.Datum) {
+	lengthDatum := input.NewDatum("length-code").WithValue(value)
+	lengthDescDatum := input.NewDatum("length-description")
+
+	result := validator.ValidateLength(value)
+	switch {
+	case errors.Is(result.Err, cont.ErrLengthCodeFormat):
+		return newValidateError(result.Err, fmt.Sprintf("%s is not a %s or a %s",
+				au.Underline("length code"),
+				au.Bold("valid number"),
+				au.Bold("valid character"))),
+			nil,
+			[]input.Datum{lengthDatum, lengthDescDatum}
+	case errors.Is(result.Err, cont.ErrLengthCodeUnknown):
+		return newValidateError(result.Err, fmt.Sprintf("%s is not %s",
+				au.Underline("length code"),
+				au.Bold("valid"))),
+			nil,
+			[]input.Datum{lengthDatum, lengthDescDatum}
+	}
+	return nil,
+		[]string{fmt.Sprintf("length: %s", result.Length)},
+		[]input.Datum{lengthDatum, lengthDescDatum.WithValue(string(result.Length))}
+}
+
+func newHeightWidthInput(validator *cont.Validator) func() input.Input {
 	heightWidth := input.NewInput(
 		1,
 		regexp.MustCompile(`[A-Za-z\d]`).FindStringIndex,
 		func(value string, _ []string) (error, []string, []input.Datum) {
-			heightWidthDatum := input.NewDatum("height-width-code").WithValue(value)
-			heightDescDatum := input.NewDatum("height-description")
-			widthDescDatum := input.NewDatum("width-description")
-			if value == "" {
-				return newValidateError(fmt.Sprintf("%s is not a %s or a %s",
-						au.Underline("height and width code"),
-						au.Bold("valid number"),
-						au.Bold("valid character"))),
+			return validateHeightWidth(validator, value)
+		})
+	heightWidth.SetToUpper()
+	return func() input.Input { return heightWidth }
+}
+
+func validateHeightWidth(validator *cont.Validator, value string) (error, []string, []input.Datum) {
+	heightWidthDatum := input.NewDatum("height-width-code").WithValue(value)
+	heightDescDatum := input.NewDatum("height-description")
+	widthDescDatum := input.NewDatum("width-description")
+
+	result := validator.ValidateHeightWidth(value)
+	switch {
+	case errors.Is(result.Err, cont.ErrHeightWidthCodeFormat):
+		return newValidateError(result.Err, fmt.Sprintf("%s is not a %s or a %s",
+				au.Underline("height and width code"),
+				au.Bold("valid number"),
+				au.Bold("valid character"))),
+			nil,
+			[]input.Datum{heightWidthDatum, heightDescDatum, widthDescDatum}
+	case errors.Is(result.Err, cont.ErrHeightWidthCodeUnknown):
+		return newValidateError(result.Err, fmt.Sprintf("%s is not %s",
+				au.Underline("height and width code"),
+				au.Bold("valid"))),
+			nil,
+			[]input.Datum{heightWidthDatum, heightDescDatum, widthDescDatum}
+	}
+	return nil,
+		[]string{
+			fmt.Sprintf("height: %s", result.Height),
+			fmt.Sprintf("width:  %s", result.Width),
+		},
+		[]input.Datum{
+			heightWidthDatum,
+			heightDescDatum.WithValue(string(result.Height)),
+			widthDescDatum.WithValue(string(result.Width)),
+		}
+}
+
+func newTypeAndGroupInput(validator *cont.Validator) func() input.Input {
+	typeAndGroup := input.NewInput(
+		2,
+		regexp.MustCompile(`[A-Za-z\d]{2}`).FindStringIndex,
+		func(value string, _ []string) (error, []string, []input.Datum) {
+			return validateTypeAndGroup(validator, value)
+		})
+	typeAndGroup.SetToUpper()
+	return func() input.Input { return typeAndGroup }
+}
+
+func validateTypeAndGroup(validator *cont.Validator, value string) (error, []string, []input.Datum) {
+	typeDatum := input.NewDatum("type-code").WithValue(value)
+	typeDescDatum := input.NewDatum("type-description")
+	groupDescDatum := input.NewDatum("group-description")
+
+	result := validator.ValidateType(value)
+	switch {
+	case errors.Is(result.Err, cont.ErrTypeCodeFormat):
+		return newValidateError(result.Err, fmt.Sprintf("%s is not a %s or a %s",
+				au.Underline("type code"),
+				au.Bold("valid number"),
+				au.Bold("valid character"))),
+			nil,
+			[]input.Datum{typeDatum, typeDescDatum, groupDescDatum}
+	case errors.Is(result.Err, cont.ErrTypeCodeUnknown):
+		return newValidateError(result.Err, fmt.Sprintf("%s is not %s",
+				au.Underline("type code"),
+				au.Bold("valid"))),
+			nil,
+			[]input.Datum{typeDatum, typeDescDatum, groupDescDatum}
+	}
+	return nil,
+		[]string{
+			fmt.Sprintf("type:  %s", result.TypeInfo),
+			fmt.Sprintf("group: %s", result.GroupInfo),
+		},
+		[]input.Datum{
+			typeDatum,
+			typeDescDatum.WithValue(string(result.TypeInfo)),
+			groupDescDatum.WithValue(string(result.GroupInfo)),
+		}
+}
+
+// legacyMatchIndex returns a function that matches count digits at the beginning of
+// the input. Only non-alphanumeric characters are skipped, so legacy codes are not
+// matched in arbitrary input.
+func legacyMatchIndex(count int) func(in string) []int {
+	legacyCode := regexp.MustCompile(fmt.Sprintf(`^[^A-Za-z\d]*(\d{%d})`, count))
+	return func(in string) []int {
+		loc := legacyCode.FindStringSubmatchIndex(in)
+		if loc == nil {
+			return nil
+		}
+		return loc[2:4]
+	}
+}
+
+// newLegacyLengthInput returns an input for a length code of the size and type code
+// table of 1984. Legacy codes have digits only and are validated as translated current codes.
+func newLegacyLengthInput(validator *cont.Validator, legacyDecoder data.LegacySizeTypeDecoder) func() input.Input {
+	length := input.NewInput(
+		1,
+		legacyMatchIndex(1),
+		func(value string, _ []string) (error, []string, []input.Datum) {
+			found, code := legacyDecoder.DecodeLength(value)
+			if !found {
+				return newValidateError(cont.WithValue(cont.ErrLengthCodeUnknown, value, "known legacy length code"), fmt.Sprintf("%s is not %s",
+						au.Underline("legacy length code"),
+						au.Bold("valid"))),
 					nil,
-					[]input.Datum{heightWidthDatum, heightDescDatum, widthDescDatum}
+					[]input.Datum{input.NewDatum("length-code"), input.NewDatum("length-description")}
 			}
+			return validateLength(validator, code)
+		})
+	return func() input.Input { return length }
+}
 
-			found, height, width := heightWidthDecoder.Decode(value)
+func newLegacyHeightWidthInput(validator *cont.Validator, legacyDecoder data.LegacySizeTypeDecoder) func() input.Input {
+	heightWidth := input.NewInput(
+		1,
+		legacyMatchIndex(1),
+		func(value string, _ []string) (error, []string, []input.Datum) {
+			found, code := legacyDecoder.DecodeHeightWidth(value)
 			if !found {
-				return newValidateError(fmt.Sprintf("%s is not %s",
-						au.Underline("height and width code"),
+				return newValidateError(cont.WithValue(cont.ErrHeightWidthCodeUnknown, value, "known legacy height and width code"), fmt.Sprintf("%s is not %s",
+						au.Underline("legacy height and width code"),
 						au.Bold("valid"))),
 					nil,
-					[]input.Datum{heightWidthDatum, heightDescDatum, widthDescDatum}
+					[]input.Datum{
+						input.NewDatum("height-width-code"),
+						input.NewDatum("height-description"),
+						input.NewDatum("width-description"),
+					}
 			}
-			return nil,
-				[]string{
-					fmt.Sprintf("height: %s", height),
-					fmt.Sprintf("width:  %s", width),
-				},
-				[]input.Datum{
-					heightWidthDatum,
-					heightDescDatum.WithValue(string(height)),
-					widthDescDatum.WithValue(string(width)),
-				}
+			return validateHeightWidth(validator, code)
 		})
-	heightWidth.SetToUpper()
 	return func() input.Input { return heightWidth }
 }
 
-func newTypeAndGroupInput(typeDecoder data.TypeDecoder) func() input.Input {
+func newLegacyTypeAndGroupInput(validator *cont.Validator, legacyDecoder data.LegacySizeTypeDecoder) func() input.Input {
 	typeAndGroup := input.NewInput(
 		2,
-		regexp.MustCompile(`[A-Za-z\d]{2}`).FindStringIndex,
-		func(value string, _ []string) (error, []string, []input.Datum) {
-			typeDatum := input.NewDatum("type-code").WithValue(value)
-			typeDescDatum := input.NewDatum("type-description")
-			groupDescDatum := input.NewDatum("group-description")
-			if value == "" {
-				return newValidateError(fmt.Sprintf("%s is not a %s or a %s",
-						au.Underline("type code"),
-						au.Bold("valid number"),
-						au.Bold("valid character"))),
-					nil,
-					[]input.Datum{typeDatum, typeDescDatum, groupDescDatum}
-			}
+		legacyMatchIndex(2),
+		func(value string, previousValues []string) (error, []string, []input.Datum) {
+			legacyCode := previousValues[1] + previousValues[0] + value
+			legacyCodeDatum := input.NewDatum("legacy-size-type-code").WithValue(legacyCode)
+			sizeTypeCodeDatum := input.NewDatum("size-type-code")
 
-			found, typeInfo, groupInfo := typeDecoder.Decode(value)
+			found, code := legacyDecoder.DecodeType(value)
 			if !found {
-				return newValidateError(fmt.Sprintf("%s is not %s",
-						au.Underline("type code"),
+				return newValidateError(cont.WithValue(cont.ErrTypeCodeUnknown, value, "known legacy type code"), fmt.Sprintf("%s is not %s",
+						au.Underline("legacy type code"),
 						au.Bold("valid"))),
 					nil,
-					[]input.Datum{typeDatum, typeDescDatum, groupDescDatum}
+					[]input.Datum{
+						input.NewDatum("type-code"),
+						input.NewDatum("type-description"),
+						input.NewDatum("group-description"),
+						legacyCodeDatum,
+						sizeTypeCodeDatum,
+					}
 			}
-			return nil,
-				[]string{
-					fmt.Sprintf("type:  %s", typeInfo),
-					fmt.Sprintf("group: %s", groupInfo),
-				},
-				[]input.Datum{
-					typeDatum,
-					typeDescDatum.WithValue(string(typeInfo)),
-					groupDescDatum.WithValue(string(groupInfo)),
-				}
+
+			err, lines, datums := validateTypeAndGroup(validator, code)
+
+			foundLength, lengthCode := legacyDecoder.DecodeLength(previousValues[1])
+			foundHeightWidth, heightWidthCode := legacyDecoder.DecodeHeightWidth(previousValues[0])
+			if foundLength && foundHeightWidth {
+				sizeTypeCode := lengthCode + heightWidthCode + code
+				sizeTypeCodeDatum = sizeTypeCodeDatum.WithValue(sizeTypeCode)
+				lines = append([]string{
+					fmt.Sprintf("%s %s is %s", au.Underline("legacy code"), legacyCode, au.Green(sizeTypeCode)),
+				}, lines...)
+			}
+			return err, lines, append(datums, legacyCodeDatum, sizeTypeCodeDatum)
 		})
-	typeAndGroup.SetToUpper()
 	return func() input.Input { return typeAndGroup }
 }
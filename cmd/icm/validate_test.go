@@ -1,10 +1,21 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/meyermarcel/icm/configs"
+	"github.com/meyermarcel/icm/cont"
+	"github.com/meyermarcel/icm/input"
 )
 
 func Test_singleLine(t *testing.T) {
@@ -146,6 +157,43 @@ func Test_validateCmd(t *testing.T) {
   │
   └─ length: some-length
 
+`,
+		},
+		{
+			"Validate legacy size and type",
+			[]string{" 43 10 "},
+			nil,
+			false,
+			`
+  43 10  ✔
+  ↑↑  ↑
+  ││  └─ legacy code 4310 is 42G1
+  ││     type:  some-type
+  ││     group: some-group
+  ││
+  │└─ height: some-height
+  │   width:  some-width
+  │
+  └─ length: some-length
+
+`,
+		},
+		{
+			"Validate unknown legacy size and type",
+			[]string{" 43 99 "},
+			[]configOverride{{configs.FlagNames.Output, "csv"}},
+			true,
+			`pattern;owner-code;company;city;country;owner-source;equipment-category-id;equipment-category;serial-number;check-digit;calculated-check-digit;valid-check-digit;possible-transposition-error;length-code;length-description;height-width-code;height-description;width-description;type-code;type-description;group-description;legacy-size-type-code;size-type-code;error-codes
+legacy-size-type;;;;;;;;;;;;;4;some-length;2;some-height;some-width;;;;4399;;TYPE_CODE_UNKNOWN
+`,
+		},
+		{
+			"Validate container number with legacy size and type",
+			[]string{"abc u 123456 0 4310"},
+			[]configOverride{{configs.FlagNames.Output, "csv"}},
+			false,
+			`pattern;owner-code;company;city;country;owner-source;equipment-category-id;equipment-category;serial-number;check-digit;calculated-check-digit;valid-check-digit;possible-transposition-error;length-code;length-description;height-width-code;height-description;width-description;type-code;type-description;group-description;legacy-size-type-code;size-type-code;error-codes
+container-number-legacy-size-type;ABC;some-company;some-city;some-country;;U;some-equip-cat-ID;123456;0;0;true;;4;some-length;2;some-height;some-width;G1;some-type;some-group;4310;42G1;
 `,
 		},
 		{
@@ -323,6 +371,33 @@ func Test_validateCmd(t *testing.T) {
       some-city
       some-country
 
+`,
+		},
+		{
+			"Validate ABC U 681304 0 with csv output and first-line match",
+			[]string{"ABC U 681304 0"},
+			[]configOverride{{configs.FlagNames.Output, "csv"}, {configs.FlagNames.Match, matchFirstLine}},
+			false,
+			`owner-code;company;city;country;owner-source;equipment-category-id;equipment-category;serial-number;check-digit;calculated-check-digit;valid-check-digit;possible-transposition-error;error-codes
+ABC;some-company;some-city;some-country;;U;some-equip-cat-ID;681304;0;0;true;ABC U 681034 0, ABC U 681340 0;
+`,
+		},
+		{
+			"Validate ABC U 681304 0 with json output and first-line match",
+			[]string{"ABC U 681304 0"},
+			[]configOverride{{configs.FlagNames.Output, "json"}, {configs.FlagNames.Match, matchFirstLine}},
+			false,
+			`[
+{"owner-code":"ABC","company":"some-company","city":"some-city","country":"some-country","owner-source":null,"equipment-category-id":"U","equipment-category":"some-equip-cat-ID","serial-number":"681304","check-digit":0,"calculated-check-digit":0,"valid-check-digit":true,"possible-transposition-error":["ABC U 681034 0","ABC U 681340 0"],"errors":[]}
+]
+`,
+		},
+		{
+			"Validate ABC U 123123 1 with ndjson output and first-line match",
+			[]string{"abc u 123123 1"},
+			[]configOverride{{configs.FlagNames.Output, "ndjson"}, {configs.FlagNames.Match, matchFirstLine}},
+			true,
+			`{"owner-code":"ABC","company":"some-company","city":"some-city","country":"some-country","owner-source":null,"equipment-category-id":"U","equipment-category":"some-equip-cat-ID","serial-number":"123123","check-digit":1,"calculated-check-digit":7,"valid-check-digit":false,"possible-transposition-error":[],"errors":[{"code":"CHECK_DIGIT_MISMATCH","part":"check-digit","value":"1","expected":"7","message":"calculated check digit is 7"}]}
 `,
 		},
 		{
@@ -330,8 +405,35 @@ func Test_validateCmd(t *testing.T) {
 			[]string{"ABC U 681304 0"},
 			[]configOverride{{configs.FlagNames.Output, "csv"}},
 			false,
-			`owner-code;company;city;country;equipment-category-id;equipment-category;serial-number;check-digit;calculated-check-digit;valid-check-digit;possible-transposition-error
-ABC;some-company;some-city;some-country;U;some-equip-cat-ID;681304;0;0;true;ABC U 681034 0, ABC U 681340 0
+			`pattern;owner-code;company;city;country;owner-source;equipment-category-id;equipment-category;serial-number;check-digit;calculated-check-digit;valid-check-digit;possible-transposition-error;length-code;length-description;height-width-code;height-description;width-description;type-code;type-description;group-description;legacy-size-type-code;size-type-code;error-codes
+container-number;ABC;some-company;some-city;some-country;;U;some-equip-cat-ID;681304;0;0;true;ABC U 681034 0, ABC U 681340 0;;;;;;;;;;;
+`,
+		},
+		{
+			"Validate ABC U 681304 0 with json output",
+			[]string{"ABC U 681304 0"},
+			[]configOverride{{configs.FlagNames.Output, "json"}},
+			false,
+			`[
+{"pattern":"container-number","owner-code":"ABC","company":"some-company","city":"some-city","country":"some-country","owner-source":null,"equipment-category-id":"U","equipment-category":"some-equip-cat-ID","serial-number":"681304","check-digit":0,"calculated-check-digit":0,"valid-check-digit":true,"possible-transposition-error":["ABC U 681034 0","ABC U 681340 0"],"length-code":null,"length-description":null,"height-width-code":null,"height-description":null,"width-description":null,"type-code":null,"type-description":null,"group-description":null,"legacy-size-type-code":null,"size-type-code":null,"errors":[]}
+]
+`,
+		},
+		{
+			"Validate ABC U 123123 1 with ndjson output",
+			[]string{"abc u 123123 1"},
+			[]configOverride{{configs.FlagNames.Output, "ndjson"}},
+			true,
+			`{"pattern":"container-number","owner-code":"ABC","company":"some-company","city":"some-city","country":"some-country","owner-source":null,"equipment-category-id":"U","equipment-category":"some-equip-cat-ID","serial-number":"123123","check-digit":1,"calculated-check-digit":7,"valid-check-digit":false,"possible-transposition-error":[],"length-code":null,"length-description":null,"height-width-code":null,"height-description":null,"width-description":null,"type-code":null,"type-description":null,"group-description":null,"legacy-size-type-code":null,"size-type-code":null,"errors":[{"code":"CHECK_DIGIT_MISMATCH","part":"check-digit","value":"1","expected":"7","message":"calculated check digit is 7"}]}
+`,
+		},
+		{
+			"Validate XYZ U 123 with error codes in csv output",
+			[]string{"xyz u 123"},
+			[]configOverride{{configs.FlagNames.Output, "csv"}, {configs.FlagNames.Pattern, containerNumber}},
+			true,
+			`owner-code;company;city;country;owner-source;equipment-category-id;equipment-category;serial-number;check-digit;calculated-check-digit;valid-check-digit;possible-transposition-error;error-codes
+;;;;;U;some-equip-cat-ID;;1;;false;;OWNER_NOT_REGISTERED, SERIAL_FORMAT, CHECK_DIGIT_NOT_CALCULABLE
 `,
 		},
 	}
@@ -345,6 +447,7 @@ ABC;some-company;some-city;some-country;U;some-equip-cat-ID;681304;0;0;true;ABC
 					&dummyLengthDecoder{},
 					&dummyHeightWidthDecoder{},
 					&dummyTypeDecoder{},
+					&dummyLegacySizeTypeDecoder{},
 				},
 			}
 
@@ -353,10 +456,87 @@ ABC;some-company;some-city;some-country;U;some-equip-cat-ID;681304;0;0;true;ABC
 				config.Map[override.name] = override.value
 			}
 
-			cmd, err := newValidateCmd(nil, writer, config, d)
+			cmd, err := newValidateCmd(nil, writer, io.Discard, config, d)
+			if err != nil {
+				t.Errorf("newValidateCmd: %v", err)
+			}
+
+			if got := cmd.RunE(cmd, tt.args); (got == nil) == tt.wantErr {
+				t.Errorf("got = %v, wantErr is %v", got, tt.wantErr)
+			}
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("gotWriter = %v, want %v", gotWriter, tt.wantWriter)
+			}
+		})
+	}
+}
+
+func Test_validateCmdSuggest(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		output     string
+		wantErr    bool
+		wantWriter string
+	}{
+		{
+			"Suggest for transposed serial number and check digit with fancy output",
+			[]string{"abc u 123450 6"},
+			"fancy",
+			true,
+			`
+  ABC U 123450 6  ✘
+   ↑  ↑        ↑
+   │  │        └─ calculated check digit is 8
+   │  │           Did you mean:
+   │  │             ABC U 123456 0
+   │  │             ABC H 123450 6
+   │  │             ABC R 123450 6
+   │  │             ABC U 123450 8
+   │  │             ABC U 123457 6
+   │  │
+   │  └─ some-equip-cat-ID
+   │
+   └─ some-company
+      some-city
+      some-country
+
+`,
+		},
+		{
+			"Suggest for confused owner code matching size and type with csv output",
+			[]string{"a8c u 123456 0"},
+			"csv",
+			false,
+			`pattern;owner-code;company;city;country;owner-source;equipment-category-id;equipment-category;serial-number;check-digit;calculated-check-digit;valid-check-digit;possible-transposition-error;length-code;length-description;height-width-code;height-description;width-description;type-code;type-description;group-description;legacy-size-type-code;size-type-code;suggestions;error-codes
+size-type;;;;;;;;;;;;;A;some-length;8;some-height;some-width;12;some-type;some-group;;;ABC U 123456 0, ABC A 123456 0, ABC D 123456 0, ABC K 123456 0, ABC N 123456 0;
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+			d := decoders{
+				ownerDecodeUpdater: &dummyOwnerDecodeUpdater{},
+				equipCatDecoder:    &dummyEquipCatDecoder{},
+				sizeTypeDecoders: sizeTypeDecoders{
+					&dummyLengthDecoder{},
+					&dummyHeightWidthDecoder{},
+					&dummyTypeDecoder{},
+					&dummyLegacySizeTypeDecoder{},
+				},
+			}
+
+			config, _ := configs.ReadConfig(configs.DefaultConfig())
+			config.Map[configs.FlagNames.Output] = tt.output
+
+			cmd, err := newValidateCmd(nil, writer, io.Discard, config, d)
 			if err != nil {
 				t.Errorf("newValidateCmd: %v", err)
 			}
+			if err := cmd.Flags().Set("suggest", "true"); err != nil {
+				t.Errorf("set suggest flag: %v", err)
+			}
 
 			if got := cmd.RunE(cmd, tt.args); (got == nil) == tt.wantErr {
 				t.Errorf("got = %v, wantErr is %v", got, tt.wantErr)
@@ -367,3 +547,373 @@ ABC;some-company;some-city;some-country;U;some-equip-cat-ID;681304;0;0;true;ABC
 		})
 	}
 }
+
+func Test_validateCmdMixedLines(t *testing.T) {
+	const mixedLines = `abc
+abc u 123123 7
+20g1
+abc u 123123 7 20g1
+`
+	tests := []struct {
+		name       string
+		output     string
+		match      string
+		wantWriter string
+	}{
+		{
+			"Validate mixed lines per line with csv output",
+			"csv",
+			matchPerLine,
+			`pattern;owner-code;company;city;country;owner-source;equipment-category-id;equipment-category;serial-number;check-digit;calculated-check-digit;valid-check-digit;possible-transposition-error;length-code;length-description;height-width-code;height-description;width-description;type-code;type-description;group-description;legacy-size-type-code;size-type-code;error-codes
+owner;ABC;some-company;some-city;some-country;;;;;;;;;;;;;;;;;;;
+container-number;ABC;some-company;some-city;some-country;;U;some-equip-cat-ID;123123;7;7;true;;;;;;;;;;;;
+size-type;;;;;;;;;;;;;2;some-length;0;some-height;some-width;G1;some-type;some-group;;;
+container-number-size-type;ABC;some-company;some-city;some-country;;U;some-equip-cat-ID;123123;7;7;true;;2;some-length;0;some-height;some-width;G1;some-type;some-group;;;
+`,
+		},
+		{
+			"Validate mixed lines per line with ndjson output",
+			"ndjson",
+			matchPerLine,
+			`{"pattern":"owner","owner-code":"ABC","company":"some-company","city":"some-city","country":"some-country","owner-source":null,"equipment-category-id":null,"equipment-category":null,"serial-number":null,"check-digit":null,"calculated-check-digit":null,"valid-check-digit":null,"possible-transposition-error":null,"length-code":null,"length-description":null,"height-width-code":null,"height-description":null,"width-description":null,"type-code":null,"type-description":null,"group-description":null,"legacy-size-type-code":null,"size-type-code":null,"errors":[]}
+{"pattern":"container-number","owner-code":"ABC","company":"some-company","city":"some-city","country":"some-country","owner-source":null,"equipment-category-id":"U","equipment-category":"some-equip-cat-ID","serial-number":"123123","check-digit":7,"calculated-check-digit":7,"valid-check-digit":true,"possible-transposition-error":[],"length-code":null,"length-description":null,"height-width-code":null,"height-description":null,"width-description":null,"type-code":null,"type-description":null,"group-description":null,"legacy-size-type-code":null,"size-type-code":null,"errors":[]}
+{"pattern":"size-type","owner-code":null,"company":null,"city":null,"country":null,"owner-source":null,"equipment-category-id":null,"equipment-category":null,"serial-number":null,"check-digit":null,"calculated-check-digit":null,"valid-check-digit":null,"possible-transposition-error":null,"length-code":"2","length-description":"some-length","height-width-code":"0","height-description":"some-height","width-description":"some-width","type-code":"G1","type-description":"some-type","group-description":"some-group","legacy-size-type-code":null,"size-type-code":null,"errors":[]}
+{"pattern":"container-number-size-type","owner-code":"ABC","company":"some-company","city":"some-city","country":"some-country","owner-source":null,"equipment-category-id":"U","equipment-category":"some-equip-cat-ID","serial-number":"123123","check-digit":7,"calculated-check-digit":7,"valid-check-digit":true,"possible-transposition-error":[],"length-code":"2","length-description":"some-length","height-width-code":"0","height-description":"some-height","width-description":"some-width","type-code":"G1","type-description":"some-type","group-description":"some-group","legacy-size-type-code":null,"size-type-code":null,"errors":[]}
+`,
+		},
+		{
+			"Validate mixed lines with pattern of first line",
+			"csv",
+			matchFirstLine,
+			`owner-code;company;city;country;owner-source;error-codes
+ABC;some-company;some-city;some-country;;
+ABC;some-company;some-city;some-country;;
+;;;;;OWNER_CODE_FORMAT
+ABC;some-company;some-city;some-country;;
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+			d := decoders{
+				ownerDecodeUpdater: &dummyOwnerDecodeUpdater{},
+				equipCatDecoder:    &dummyEquipCatDecoder{},
+				sizeTypeDecoders: sizeTypeDecoders{
+					&dummyLengthDecoder{},
+					&dummyHeightWidthDecoder{},
+					&dummyTypeDecoder{},
+					&dummyLegacySizeTypeDecoder{},
+				},
+			}
+
+			config, _ := configs.ReadConfig(configs.DefaultConfig())
+			config.Map[configs.FlagNames.Output] = tt.output
+			config.Map[configs.FlagNames.Match] = tt.match
+
+			cmd, err := newValidateCmd(strings.NewReader(mixedLines), writer, io.Discard, config, d)
+			if err != nil {
+				t.Errorf("newValidateCmd: %v", err)
+			}
+
+			_ = cmd.RunE(cmd, nil)
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("gotWriter = %v, want %v", gotWriter, tt.wantWriter)
+			}
+		})
+	}
+}
+
+func Test_validateCmdSummary(t *testing.T) {
+	const lines = `abc u 123123 7 20g1
+abc u 123123 1
+xyz u 123456 0
+abc u 123123 7 20g1
+`
+	tests := []struct {
+		name          string
+		output        string
+		failOn        string
+		wantErr       bool
+		wantWriterErr string
+		wantSummary   string
+	}{
+		{
+			"Print summary to writerErr and fail on any invalid line",
+			"csv",
+			failOnAnyInvalid,
+			true,
+			`Summary
+  lines:          4
+  valid:          2
+  invalid:        2
+  check digit 10: 0
+  error-prone:    0
+Invalid by reason
+  CHECK_DIGIT_MISMATCH: 1
+  OWNER_NOT_REGISTERED: 1
+Unknown owners
+  XYZ: 1
+Owners
+  ABC: 3
+Types
+  20G1: 2
+`,
+			"",
+		},
+		{
+			"Print summary as trailing JSON document and fail never",
+			"ndjson",
+			failOnNever,
+			false,
+			"",
+			`{"summary":{"lines":4,"valid":2,"invalid":2,"invalid-by-reason":{"CHECK_DIGIT_MISMATCH":1,"OWNER_NOT_REGISTERED":1},"check-digit-10":0,"error-prone":0,"unknown-owners":{"XYZ":1},"owners":{"ABC":3},"types":{"20G1":2}}}
+`,
+		},
+		{
+			"Do not fail if not all lines are invalid",
+			"json",
+			failOnAllInvalid,
+			false,
+			"",
+			`{"summary":{"lines":4,"valid":2,"invalid":2,"invalid-by-reason":{"CHECK_DIGIT_MISMATCH":1,"OWNER_NOT_REGISTERED":1},"check-digit-10":0,"error-prone":0,"unknown-owners":{"XYZ":1},"owners":{"ABC":3},"types":{"20G1":2}}}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+			writerErr := &bytes.Buffer{}
+			d := decoders{
+				ownerDecodeUpdater: &dummyOwnerDecodeUpdater{},
+				equipCatDecoder:    &dummyEquipCatDecoder{},
+				sizeTypeDecoders: sizeTypeDecoders{
+					&dummyLengthDecoder{},
+					&dummyHeightWidthDecoder{},
+					&dummyTypeDecoder{},
+					&dummyLegacySizeTypeDecoder{},
+				},
+			}
+
+			config, _ := configs.ReadConfig(configs.DefaultConfig())
+			config.Map[configs.FlagNames.Output] = tt.output
+
+			cmd, err := newValidateCmd(strings.NewReader(lines), writer, writerErr, config, d)
+			if err != nil {
+				t.Errorf("newValidateCmd: %v", err)
+			}
+			if err := cmd.Flags().Set("summary", "true"); err != nil {
+				t.Errorf("set summary flag: %v", err)
+			}
+			if err := cmd.Flags().Set("fail-on", tt.failOn); err != nil {
+				t.Errorf("set fail-on flag: %v", err)
+			}
+
+			got := cmd.RunE(cmd, nil)
+			if (got != nil) != tt.wantErr {
+				t.Errorf("got = %v, wantErr is %v", got, tt.wantErr)
+			}
+			var errValidate *validateError
+			if got != nil && !errors.As(got, &errValidate) {
+				t.Errorf("got = %v, want validateError", got)
+			}
+			if gotWriterErr := writerErr.String(); gotWriterErr != tt.wantWriterErr {
+				t.Errorf("gotWriterErr = %v, want %v", gotWriterErr, tt.wantWriterErr)
+			}
+			if gotWriter := writer.String(); !strings.HasSuffix(gotWriter, "\n"+tt.wantSummary) {
+				t.Errorf("gotWriter = %v, want suffix %v", gotWriter, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func Test_failOnValue_err(t *testing.T) {
+	errLine := errors.New("invalid line")
+	tests := []struct {
+		name    string
+		value   string
+		lines   int
+		invalid int
+		wantErr error
+	}{
+		{"Fail on any invalid line", failOnAnyInvalid, 2, 1, errLine},
+		{"Do not fail on only valid lines", failOnAnyInvalid, 2, 0, nil},
+		{"Fail on all invalid lines", failOnAllInvalid, 2, 2, errLine},
+		{"Do not fail on some invalid lines", failOnAllInvalid, 2, 1, nil},
+		{"Do not fail on no lines", failOnAllInvalid, 0, 0, nil},
+		{"Never fail", failOnNever, 2, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &failOnValue{value: tt.value}
+			var firstErr error
+			if tt.invalid > 0 {
+				firstErr = errLine
+			}
+			if err := f.err(firstErr, tt.lines, tt.invalid); !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("err() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// validateSequential is the implementation of the validate loop before the pipeline.
+// It validates lines sequentially and flushes after every record.
+func validateSequential(scanner *bufio.Scanner, validate func(line string) ([]input.Input, error), printer *input.CSVPrinter) error {
+	var inputErr error
+	for scanner.Scan() {
+		var inputs []input.Input
+		inputs, inputErr = validate(scanner.Text())
+		if err := printer.Print(inputs); err != nil {
+			return err
+		}
+		if err := printer.Flush(); err != nil {
+			return err
+		}
+	}
+	return inputErr
+}
+
+func Benchmark_validate(b *testing.B) {
+	d := decoders{
+		ownerDecodeUpdater: &dummyOwnerDecodeUpdater{},
+		equipCatDecoder:    &dummyEquipCatDecoder{},
+		sizeTypeDecoders: sizeTypeDecoders{
+			&dummyLengthDecoder{},
+			&dummyHeightWidthDecoder{},
+			&dummyTypeDecoder{},
+			&dummyLegacySizeTypeDecoder{},
+		},
+	}
+	config, _ := configs.ReadConfig(configs.DefaultConfig())
+
+	generator, _ := cont.NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).
+		OwnerCodes([]string{"ABC"}).
+		Count(10000).
+		Build()
+	lines := &strings.Builder{}
+	for generator.Generate() {
+		lines.WriteString(generator.ContNum().String())
+		lines.WriteString(" 22G1\n")
+	}
+
+	patterns := newAutoPattern(config, d)
+	validate := newLineValidator(patterns, "", true, false, true, d.newValidator(), config)
+	headers := append(unionHeaders(patterns, false), errorCodesHeader)
+
+	newCSVPrinter := func() *input.CSVPrinter {
+		csvWriter := csv.NewWriter(io.Discard)
+		csvPrinter := input.NewCSVPrinter(csvWriter, false)
+		csvPrinter.SetHeaders(headers)
+		return csvPrinter
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		b.SetBytes(int64(lines.Len()))
+		for range b.N {
+			scanner := bufio.NewScanner(strings.NewReader(lines.String()))
+			_ = validateSequential(scanner, validate, newCSVPrinter())
+		}
+	})
+	workerCounts := []int{1, 2, 4, runtime.GOMAXPROCS(0)}
+	slices.Sort(workerCounts)
+	for _, workers := range slices.Compact(workerCounts) {
+		b.Run(fmt.Sprintf("pipeline-%d-workers", workers), func(b *testing.B) {
+			b.SetBytes(int64(lines.Len()))
+			for range b.N {
+				pipeline := input.NewPipeline(validate, newCSVPrinter())
+				pipeline.SetWorkers(workers)
+				_ = pipeline.Run(strings.NewReader(lines.String()))
+			}
+		})
+	}
+}
+
+type sourcedOwnerDecoder struct {
+	dummyOwnerDecodeUpdater
+}
+
+func (sourcedOwnerDecoder) Source(_ string) string {
+	return "custom-owner.csv"
+}
+
+func (sourcedOwnerDecoder) Blocked(code string) (bool, string) {
+	return code == "XYZ", "retired"
+}
+
+func Test_validateCmdOwnerSource(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		output     string
+		wantErr    bool
+		wantWriter string
+	}{
+		{
+			"Validate owner from source",
+			[]string{"abc"},
+			"fancy",
+			false,
+			`
+  ABC  ✔
+   ↑
+   └─ some-company
+      some-city
+      some-country
+      owner from custom-owner.csv
+
+`,
+		},
+		{
+			"Validate owner from source with csv output",
+			[]string{"abc"},
+			"csv",
+			false,
+			`pattern;owner-code;company;city;country;owner-source;equipment-category-id;equipment-category;serial-number;check-digit;calculated-check-digit;valid-check-digit;possible-transposition-error;length-code;length-description;height-width-code;height-description;width-description;type-code;type-description;group-description;legacy-size-type-code;size-type-code;error-codes
+owner;ABC;some-company;some-city;some-country;custom-owner.csv;;;;;;;;;;;;;;;;;;
+`,
+		},
+		{
+			"Validate blocked owner",
+			[]string{"xyz"},
+			"fancy",
+			true,
+			`
+  XYZ  ✘
+   ↑
+   └─ XYZ is blocked (retired)
+
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+			d := decoders{
+				ownerDecodeUpdater: &sourcedOwnerDecoder{},
+				equipCatDecoder:    &dummyEquipCatDecoder{},
+				sizeTypeDecoders: sizeTypeDecoders{
+					&dummyLengthDecoder{},
+					&dummyHeightWidthDecoder{},
+					&dummyTypeDecoder{},
+					&dummyLegacySizeTypeDecoder{},
+				},
+			}
+
+			config, _ := configs.ReadConfig(configs.DefaultConfig())
+			config.Map[configs.FlagNames.Output] = tt.output
+
+			cmd, err := newValidateCmd(nil, writer, io.Discard, config, d)
+			if err != nil {
+				t.Errorf("newValidateCmd: %v", err)
+			}
+
+			if got := cmd.RunE(cmd, tt.args); (got != nil) != tt.wantErr {
+				t.Errorf("got = %v, wantErr is %v", got, tt.wantErr)
+			}
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("gotWriter = %v, want %v", gotWriter, tt.wantWriter)
+			}
+		})
+	}
+}
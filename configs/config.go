@@ -15,6 +15,7 @@ type Config struct {
 func (c *Config) Overwrite(flagSet *pflag.FlagSet) {
 	for k := range map[string]bool{
 		FlagNames.Pattern:  true,
+		FlagNames.Match:    true,
 		FlagNames.NoHeader: true,
 		FlagNames.Output:   true,
 		FlagNames.SepOE:    true,
@@ -42,6 +43,10 @@ func (c *Config) Pattern() string {
 	return c.Map[FlagNames.Pattern]
 }
 
+func (c *Config) Match() string {
+	return c.Map[FlagNames.Match]
+}
+
 func (c *Config) NoHeader() bool {
 	value, _ := strconv.ParseBool(c.Map[FlagNames.NoHeader])
 	return value
@@ -97,6 +102,7 @@ const (
 // Names is the structure for the flag names.
 type Names struct {
 	Pattern  string
+	Match    string
 	NoHeader string
 	Output   string
 	SepOE    string
@@ -109,6 +115,7 @@ type Names struct {
 // FlagNames has all the flag names.
 var FlagNames = Names{
 	Pattern:  "pattern",
+	Match:    "match",
 	NoHeader: "no-header",
 	Output:   "output",
 	SepOE:    "sep-owner-equip",
@@ -121,6 +128,7 @@ var FlagNames = Names{
 // Values is the structure for the default flag values.
 type Values struct {
 	Pattern  string
+	Match    string
 	NoHeader bool
 	Output   string
 	SepOE    string
@@ -133,6 +141,7 @@ type Values struct {
 // DefaultValues has all the default values.
 var DefaultValues = Values{
 	Pattern:  "auto",
+	Match:    "per-line",
 	NoHeader: false,
 	Output:   "auto",
 	SepOE:    " ",
@@ -152,10 +161,17 @@ func DefaultConfig() []byte {
 #                size-type = matches length, width+height and type code
 ` + FlagNames.Pattern + `: ` + DefaultValues.Pattern + `
 
+# Matching mode of pattern auto
+#   per-line = matches a pattern for every line
+# first-line = matches a pattern for the first line and uses it for all lines
+` + FlagNames.Match + `: ` + DefaultValues.Match + `
+
 # Output mode
-#  auto = for a single line 'fancy' and for multiple lines 'csv' output 
-#   csv = machine readable CSV output
-# fancy = human readable fancy output
+#   auto = for a single line 'fancy' and for multiple lines 'csv' output 
+#    csv = machine readable CSV output
+#   json = machine readable JSON document with an object per line
+# ndjson = machine readable JSON object per line (newline delimited JSON)
+#  fancy = human readable fancy output
 ` + FlagNames.Output + `: ` + DefaultValues.Output + `
 
 # No header for CSV output
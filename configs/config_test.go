@@ -20,6 +20,7 @@ func TestReadConfig(t *testing.T) {
 			DefaultConfig(),
 			&Config{Map: map[string]string{
 				FlagNames.Pattern:  DefaultValues.Pattern,
+				FlagNames.Match:    DefaultValues.Match,
 				FlagNames.NoHeader: fmt.Sprintf("%t", DefaultValues.NoHeader),
 				FlagNames.Output:   DefaultValues.Output,
 				FlagNames.SepOE:    DefaultValues.SepOE,
@@ -0,0 +1,90 @@
+package configs
+
+import (
+	"fmt"
+
+	"github.com/meyermarcel/icm/cont"
+	"gopkg.in/yaml.v3"
+)
+
+// Name of the owner sources file.
+const OwnerSourcesNameWithYmlExt = "owner-sources.yml"
+
+// Types of owner sources.
+const (
+	OwnerSourceEmbedded = "embedded"
+	OwnerSourceFile     = "file"
+	OwnerSourceDir      = "dir"
+)
+
+// OwnerSource is a source of owners.
+type OwnerSource struct {
+	Type string `yaml:"type"`
+	Path string `yaml:"path"`
+	// Optional sources are skipped if path does not exist.
+	Optional bool `yaml:"optional"`
+}
+
+// OwnerSources are the sources of owners in order of precedence and the
+// blocked owner codes with their reasons.
+type OwnerSources struct {
+	Sources []OwnerSource     `yaml:"sources"`
+	Blocked map[string]string `yaml:"blocked"`
+}
+
+// ReadOwnerSources returns the read owner sources.
+func ReadOwnerSources(b []byte) (*OwnerSources, error) {
+	var s OwnerSources
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	if len(s.Sources) == 0 {
+		return nil, fmt.Errorf("no owner sources configured")
+	}
+	for i, source := range s.Sources {
+		switch source.Type {
+		case OwnerSourceEmbedded:
+		case OwnerSourceFile, OwnerSourceDir:
+			if source.Path == "" {
+				return nil, fmt.Errorf("owner source %d: %s has no path", i+1, source.Type)
+			}
+		default:
+			return nil, fmt.Errorf("owner source %d: %s is not %s, %s or %s", i+1, source.Type,
+				OwnerSourceEmbedded, OwnerSourceFile, OwnerSourceDir)
+		}
+	}
+	for code := range s.Blocked {
+		if err := cont.IsOwnerCode(code); err != nil {
+			return nil, fmt.Errorf("blocked owner code %s: %w", code, err)
+		}
+	}
+	return &s, nil
+}
+
+// DefaultOwnerSources returns default owner sources.
+func DefaultOwnerSources() []byte {
+	return []byte(`# Sources of owners in order of precedence. An owner of a source
+# overrides an owner with the same code of all sources before.
+#
+# Types of sources
+# embedded = owners built into icm
+#     file = CSV file with owner code, company, city and country separated by semicolons
+#      dir = all CSV files of a directory in order of their names
+#
+# Relative paths are relative to the directory of this file. Sources with
+# 'optional: true' are skipped if their path does not exist.
+sources:
+  - type: ` + OwnerSourceFile + `
+    path: data/owner.csv
+  - type: ` + OwnerSourceFile + `
+    path: data/custom-owner.csv
+    optional: true
+
+# Blocked owner codes with a reason, e.g. retired codes. Blocked owner codes
+# fail validation and are not used for generation.
+#
+# blocked:
+#   ABC: retired
+blocked: {}
+`)
+}
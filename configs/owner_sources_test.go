@@ -0,0 +1,62 @@
+package configs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadOwnerSources(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       []byte
+		want    *OwnerSources
+		wantErr bool
+	}{
+		{
+			"parse default owner sources",
+			DefaultOwnerSources(),
+			&OwnerSources{
+				Sources: []OwnerSource{
+					{Type: OwnerSourceFile, Path: "data/owner.csv"},
+					{Type: OwnerSourceFile, Path: "data/custom-owner.csv", Optional: true},
+				},
+				Blocked: map[string]string{},
+			},
+			false,
+		},
+		{
+			"parse all types and blocked owner codes",
+			[]byte(`sources:
+  - type: embedded
+  - type: dir
+    path: owners
+blocked:
+  ABC: retired
+`),
+			&OwnerSources{
+				Sources: []OwnerSource{
+					{Type: OwnerSourceEmbedded},
+					{Type: OwnerSourceDir, Path: "owners"},
+				},
+				Blocked: map[string]string{"ABC": "retired"},
+			},
+			false,
+		},
+		{"no sources", []byte("sources: []"), nil, true},
+		{"unknown type", []byte("sources:\n  - type: ftp\n    path: owners"), nil, true},
+		{"missing path", []byte("sources:\n  - type: file"), nil, true},
+		{"invalid blocked owner code", []byte("sources:\n  - type: embedded\nblocked:\n  AB: retired"), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadOwnerSources(tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadOwnerSources() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadOwnerSources() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
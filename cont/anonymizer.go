@@ -0,0 +1,168 @@
+package cont
+
+import (
+	"cmp"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"slices"
+)
+
+// Anonymizer maps container numbers to fictitious container numbers with a key. The
+// mapping is deterministic, so the same container number and key always result in the
+// same fictitious container number, and it is a bijection, so different container
+// numbers result in different fictitious container numbers.
+//
+// Owner codes are mapped to owner codes and serial numbers are mapped for every owner
+// code. Container numbers of the same owner have fictitious container numbers of the
+// same owner. The equipment category ID is not changed.
+//
+// The weighted sums of owner code and serial number modulo 11 are not changed, so the
+// calculated check digit is the same. Container numbers with check digit 10 keep check
+// digit 10 and invalid check digits stay invalid.
+//
+// An Anonymizer is not safe for concurrent use.
+type Anonymizer struct {
+	mac    hash.Hash
+	owners map[string]string
+	// unmappable has owner codes that cannot be mapped to an unregistered owner code.
+	unmappable map[string]bool
+	// rounds has the cached results of the round functions of the Feistel network of
+	// every owner code. Not calculated results are feistelNotCalculated.
+	rounds map[string]*[feistelRounds][1 << feistelHalfBits]uint16
+}
+
+// NewAnonymizer returns an Anonymizer for key. If isRegistered is not nil, registered
+// owner codes are only mapped to owner codes that are not registered. Not every
+// unregistered owner code can be mapped in this case, because there are less unregistered
+// owner codes than owner codes.
+func NewAnonymizer(key []byte, isRegistered func(code string) bool) *Anonymizer {
+	a := &Anonymizer{
+		mac:        hmac.New(sha256.New, key),
+		owners:     make(map[string]string),
+		unmappable: make(map[string]bool),
+		rounds:     make(map[string]*[feistelRounds][1 << feistelHalfBits]uint16),
+	}
+
+	type rankedCode struct {
+		code       string
+		registered bool
+		rank       uint64
+	}
+	// Owner codes are grouped by the weighted sum modulo 11 of the owner code, which is
+	// the check digit of the owner code with equipment category ID U and serial number 0.
+	var inputs, outputs [11][]rankedCode
+	for code := range AllOwnerCodes() {
+		class := CalcCheckDigit(code, 'U', 0)
+		registered := isRegistered != nil && isRegistered(code)
+		inputs[class] = append(inputs[class], rankedCode{code, registered, a.prf("owner-in", code, 0)})
+		if !registered {
+			outputs[class] = append(outputs[class], rankedCode{code, false, a.prf("owner-out", code, 0)})
+		}
+	}
+	for class := range 11 {
+		// Registered owner codes are first to map all of them to unregistered owner codes.
+		slices.SortFunc(inputs[class], func(x, y rankedCode) int {
+			switch {
+			case x.registered && !y.registered:
+				return -1
+			case !x.registered && y.registered:
+				return 1
+			}
+			return cmp.Compare(x.rank, y.rank)
+		})
+		slices.SortFunc(outputs[class], func(x, y rankedCode) int {
+			return cmp.Compare(x.rank, y.rank)
+		})
+		for i, in := range inputs[class] {
+			if i < len(outputs[class]) {
+				a.owners[in.code] = outputs[class][i].code
+			} else {
+				a.unmappable[in.code] = true
+			}
+		}
+	}
+	return a
+}
+
+// Anonymize returns the fictitious container number of n. The check digit of n is kept.
+func (a *Anonymizer) Anonymize(n Number) (Number, error) {
+	ownerCode, ok := a.owners[n.OwnerCode]
+	if !ok {
+		if a.unmappable[n.OwnerCode] {
+			return Number{}, fmt.Errorf("%s cannot be mapped to an unregistered owner code", n.OwnerCode)
+		}
+		return Number{}, fmt.Errorf("%s: %w", n.OwnerCode, ErrOwnerCodeFormat)
+	}
+	if n.SerialNumber < 0 || n.SerialNumber > 999999 {
+		return Number{}, fmt.Errorf("%d: %w", n.SerialNumber, ErrSerialNumFormat)
+	}
+	return Number{
+		OwnerCode:    ownerCode,
+		EquipCatID:   n.EquipCatID,
+		SerialNumber: a.permSerialNum(n.OwnerCode, n.SerialNumber),
+		CheckDigit:   n.CheckDigit,
+	}, nil
+}
+
+// permSerialNum returns the permuted serial number of serialNum for the owner code.
+// The serial number is permuted by a Feistel network on 20 bits. Results greater than
+// 999999 or with another weighted sum modulo 11 are permuted again (cycle walking).
+func (a *Anonymizer) permSerialNum(ownerCode string, serialNum int) int {
+	class := serialNumClass(serialNum)
+	x := serialNum
+	for {
+		x = a.feistel(ownerCode, x)
+		if x <= 999999 && serialNumClass(x) == class {
+			return x
+		}
+	}
+}
+
+// serialNumClass returns the weighted sum modulo 11 of serialNum.
+func serialNumClass(serialNum int) int {
+	return (CalcCheckDigit("AAA", 'U', serialNum) - CalcCheckDigit("AAA", 'U', 0) + 11) % 11
+}
+
+const (
+	feistelRounds        = 8
+	feistelHalfBits      = 10
+	feistelHalfMask      = 1<<feistelHalfBits - 1
+	feistelNotCalculated = 0xFFFF
+)
+
+// feistel returns the permuted number of x with 20 bits.
+func (a *Anonymizer) feistel(ownerCode string, x int) int {
+	rounds, ok := a.rounds[ownerCode]
+	if !ok {
+		rounds = new([feistelRounds][1 << feistelHalfBits]uint16)
+		for round := range rounds {
+			for i := range rounds[round] {
+				rounds[round][i] = feistelNotCalculated
+			}
+		}
+		a.rounds[ownerCode] = rounds
+	}
+
+	left, right := uint16(x>>feistelHalfBits), uint16(x&feistelHalfMask)
+	for round := range feistelRounds {
+		if rounds[round][right] == feistelNotCalculated {
+			rounds[round][right] = uint16(a.prf("serial", ownerCode, uint64(round)<<feistelHalfBits|uint64(right)) & feistelHalfMask)
+		}
+		left, right = right, left^rounds[round][right]
+	}
+	return int(left)<<feistelHalfBits | int(right)
+}
+
+// prf returns a pseudo random number of the key for domain, s and n. Different
+// domains result in independent pseudo random numbers.
+func (a *Anonymizer) prf(domain, s string, n uint64) uint64 {
+	a.mac.Reset()
+	a.mac.Write([]byte(domain))
+	a.mac.Write([]byte{0})
+	a.mac.Write([]byte(s))
+	a.mac.Write(binary.BigEndian.AppendUint64(nil, n))
+	return binary.BigEndian.Uint64(a.mac.Sum(nil))
+}
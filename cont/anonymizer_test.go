@@ -0,0 +1,86 @@
+package cont
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAnonymizer_Anonymize(t *testing.T) {
+	isRegistered := func(code string) bool { return code == "ABC" || code == "DEF" }
+	tests := []struct {
+		name         string
+		key          string
+		isRegistered func(string) bool
+		number       Number
+		want         string
+		wantErr      error
+	}{
+		{"Anonymize", "key", nil, Number{"ABC", 'U', 123456, 0}, "RUBU1631850", nil},
+		{"Anonymize with other key", "other-key", nil, Number{"ABC", 'U', 123456, 0}, "SBSU2886120", nil},
+		{"Anonymize with other serial number", "key", nil, Number{"ABC", 'U', 123457, 3}, "RUBU7242093", nil},
+		{"Anonymize with check digit 10", "key", nil, Number{"CMA", 'U', 163912, 0}, "KQGU4135930", nil},
+		{"Anonymize with unregistered owner codes", "key", isRegistered, Number{"ABC", 'U', 123456, 0}, "FGJU1631850", nil},
+		{"Return error for owner code", "key", nil, Number{"AB1", 'U', 123456, 0}, "", ErrOwnerCodeFormat},
+		{"Return error for serial number", "key", nil, Number{"ABC", 'U', 1000000, 0}, "", ErrSerialNumFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewAnonymizer([]byte(tt.key), tt.isRegistered).Anonymize(tt.number)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Anonymize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("Anonymize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnonymizerBijection(t *testing.T) {
+	isRegistered := func(code string) bool { return code[0] == 'A' }
+	for _, tt := range []struct {
+		name         string
+		isRegistered func(string) bool
+	}{
+		{"All owner codes", nil},
+		{"Unregistered owner codes", isRegistered},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAnonymizer([]byte("key"), tt.isRegistered)
+			seen := map[string]bool{}
+			for code, anonymized := range a.owners {
+				if seen[anonymized] {
+					t.Fatalf("owner code %s of %s is not unique", anonymized, code)
+				}
+				seen[anonymized] = true
+				if tt.isRegistered != nil && tt.isRegistered(anonymized) {
+					t.Fatalf("owner code %s of %s is registered", anonymized, code)
+				}
+				if CalcCheckDigit(code, 'U', 0) != CalcCheckDigit(anonymized, 'U', 0) {
+					t.Fatalf("owner code %s of %s has another weighted sum", anonymized, code)
+				}
+			}
+			for code := range AllOwnerCodes() {
+				if _, ok := a.owners[code]; !ok && (tt.isRegistered == nil || tt.isRegistered(code)) {
+					t.Fatalf("owner code %s is not mapped", code)
+				}
+			}
+		})
+	}
+
+	a := NewAnonymizer([]byte("key"), nil)
+	seen := map[int]bool{}
+	for serialNum := range 20000 {
+		n, _ := a.Anonymize(Number{"ABC", 'U', serialNum, 0})
+		if seen[n.SerialNumber] {
+			t.Fatalf("Anonymize() serial number %d of %d is not unique", n.SerialNumber, serialNum)
+		}
+		seen[n.SerialNumber] = true
+		if want, got := CalcCheckDigit("ABC", 'U', serialNum), CalcCheckDigit(n.OwnerCode, 'U', n.SerialNumber); got != want {
+			t.Fatalf("Anonymize() check digit of %v is %d, want %d", n, got, want)
+		}
+	}
+}
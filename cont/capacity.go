@@ -0,0 +1,66 @@
+package cont
+
+// Capacity has the count of usable container numbers of an owner in a serial number
+// range and the counts of container numbers excluded by every exclusion rule. A container
+// number can be counted for several rules.
+type Capacity struct {
+	OwnerCode string
+	// Range is the count of serial numbers in the range.
+	Range int
+	// Used is the count of container numbers already in use.
+	Used int
+	// CheckDigit10 is the count of container numbers with check digit 10.
+	CheckDigit10 int
+	// ErrorProne is the count of container numbers with error-prone serial numbers.
+	ErrorProne int
+	// Excluded is the count of container numbers excluded by the applied rules.
+	Excluded int
+	// Usable is the count of container numbers that are not excluded. A generator
+	// of only this owner and the same range and exclusions generates this count.
+	Usable int
+}
+
+// CountCapacity returns the capacity of the owner code in the serial number range from
+// start to end. If start is greater than end, the range continues at 0 after 999999
+// like the range of a generator. Container numbers of used with the owner code, the
+// equipment category ID and a serial number of the range are already in use and are
+// always excluded. Container numbers with check digit 10 and error-prone serial numbers
+// are counted and only excluded if exclCheckDigit10 and exclErrorProneSerialNumbers
+// are true.
+func CountCapacity(code string, equipCatID rune, start, end int, used []Number,
+	exclCheckDigit10, exclErrorProneSerialNumbers bool,
+) Capacity {
+	usedSerialNums := make(map[int]bool)
+	for _, n := range used {
+		if n.OwnerCode == code && n.EquipCatID == equipCatID {
+			usedSerialNums[n.SerialNumber] = true
+		}
+	}
+
+	c := Capacity{OwnerCode: code, Range: end - start + 1}
+	if start > end {
+		c.Range += 1000000
+	}
+	for i := range c.Range {
+		serialNum := (start + i) % 1000000
+		excluded := false
+		if usedSerialNums[serialNum] {
+			c.Used++
+			excluded = true
+		}
+		checkDigit := CalcCheckDigit(code, equipCatID, serialNum)
+		if checkDigit == 10 {
+			c.CheckDigit10++
+			excluded = excluded || exclCheckDigit10
+		}
+		if CheckTransposition(code, equipCatID, serialNum, checkDigit) != nil {
+			c.ErrorProne++
+			excluded = excluded || exclErrorProneSerialNumbers
+		}
+		if excluded {
+			c.Excluded++
+		}
+	}
+	c.Usable = c.Range - c.Excluded
+	return c
+}
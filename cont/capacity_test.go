@@ -0,0 +1,71 @@
+package cont
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestCountCapacity(t *testing.T) {
+	used := []Number{
+		{OwnerCode: "ABC", EquipCatID: 'U', SerialNumber: 100000},
+		{OwnerCode: "ABC", EquipCatID: 'U', SerialNumber: 100001},
+		{OwnerCode: "ABC", EquipCatID: 'U', SerialNumber: 200000},
+		{OwnerCode: "DEF", EquipCatID: 'U', SerialNumber: 100002},
+		{OwnerCode: "ABC", EquipCatID: 'J', SerialNumber: 100003},
+	}
+	type args struct {
+		start                       int
+		end                         int
+		exclCheckDigit10            bool
+		exclErrorProneSerialNumbers bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want Capacity
+	}{
+		{
+			"Count without exclusions",
+			args{100000, 199999, false, false},
+			Capacity{"ABC", 100000, 2, 9091, 7793, 2, 99998},
+		},
+		{
+			"Count with exclusions",
+			args{100000, 199999, true, true},
+			Capacity{"ABC", 100000, 2, 9091, 7793, 12860, 87140},
+		},
+		{
+			"Count range continuing at 0",
+			args{999990, 9, true, false},
+			Capacity{"ABC", 20, 0, 2, 2, 2, 18},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CountCapacity("ABC", 'U', tt.args.start, tt.args.end, used,
+				tt.args.exclCheckDigit10, tt.args.exclErrorProneSerialNumbers)
+			if got != tt.want {
+				t.Errorf("CountCapacity() = %#v, want %#v", got, tt.want)
+			}
+
+			g, err := NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).
+				OwnerCodes([]string{"ABC"}).
+				Start(tt.args.start).
+				End(tt.args.end).
+				Exclude(used).
+				ExcludeCheckDigit10(tt.args.exclCheckDigit10).
+				ExcludeErrorProneSerialNumbers(tt.args.exclErrorProneSerialNumbers).
+				Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			generated := 0
+			for g.Generate() {
+				generated++
+			}
+			if generated != got.Usable {
+				t.Errorf("generated %d container numbers, want %d usable ones", generated, got.Usable)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package cont
 
+import "fmt"
+
 // CalcCheckDigit calculates check digit for owner, equipment category ID and serial number.
 // This function was optimized for fun and has a suboptimal reading experience.
 func CalcCheckDigit(ownerCode string, equipCatID rune, serialNum int) int {
@@ -36,3 +38,60 @@ func CalcCheckDigit(ownerCode string, equipCatID rune, serialNum int) int {
 func charValue(char uint16) uint16 {
 	return char - 55 + (char-56)/10
 }
+
+// CheckDigitStep is the calculation of the product of a character of a container number.
+type CheckDigitStep struct {
+	Char rune
+	// Value is the value of a digit or letter. Letter values are 10 for A and
+	// increase by one, but skip multiples of 11 (11, 22 and 33).
+	Value int
+	// Weight is 2 to the power of the position starting with 0.
+	Weight  int
+	Product int
+}
+
+// CheckDigitCalculation has the intermediate values of the check digit calculation.
+type CheckDigitCalculation struct {
+	// Steps has a step for every character of owner code, equipment category ID and serial number.
+	Steps []CheckDigitStep
+	// Sum is the sum of all products.
+	Sum int
+	// Remainder is the sum modulo 11 and can be 10.
+	Remainder int
+	// CheckDigit is the remainder with 10 mapped to 0.
+	CheckDigit int
+}
+
+// ExplainCheckDigit returns the intermediate values of the check digit calculation for owner,
+// equipment category ID and serial number. The remainder is the same as of CalcCheckDigit.
+func ExplainCheckDigit(ownerCode string, equipCatID rune, serialNum int) CheckDigitCalculation {
+	var c CheckDigitCalculation
+	chars := fmt.Sprintf("%s%c%06d", ownerCode, equipCatID, serialNum)
+	weight := 1
+	for _, char := range chars {
+		value := letterValue(char)
+		if char >= '0' && char <= '9' {
+			value = int(char - '0')
+		}
+		step := CheckDigitStep{Char: char, Value: value, Weight: weight, Product: value * weight}
+		c.Steps = append(c.Steps, step)
+		c.Sum += step.Product
+		weight *= 2
+	}
+	c.Remainder = c.Sum % 11
+	c.CheckDigit = c.Remainder % 10
+	return c
+}
+
+// letterValue returns the value of an upper case letter. A is 10 and the values
+// increase by one, but skip multiples of 11.
+func letterValue(letter rune) int {
+	value := 10
+	for r := 'A'; r < letter; r++ {
+		value++
+		if value%11 == 0 {
+			value++
+		}
+	}
+	return value
+}
@@ -1,6 +1,7 @@
 package cont
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -65,3 +66,36 @@ func BenchmarkCalcCheckDigit(b *testing.B) {
 		CalcCheckDigit("CSQ", 'U', 305438)
 	}
 }
+
+func TestExplainCheckDigit(t *testing.T) {
+	got := ExplainCheckDigit("CSQ", 'U', 305438)
+	want := CheckDigitCalculation{
+		Steps: []CheckDigitStep{
+			{'C', 13, 1, 13},
+			{'S', 30, 2, 60},
+			{'Q', 28, 4, 112},
+			{'U', 32, 8, 256},
+			{'3', 3, 16, 48},
+			{'0', 0, 32, 0},
+			{'5', 5, 64, 320},
+			{'4', 4, 128, 512},
+			{'3', 3, 256, 768},
+			{'8', 8, 512, 4096},
+		},
+		Sum:        6185,
+		Remainder:  3,
+		CheckDigit: 3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExplainCheckDigit() = %v, want %v", got, want)
+	}
+
+	for _, ownerCode := range []string{"ABC", "NYK", "CMA", "XYZ", "LUV"} {
+		for serialNum := range 1000 {
+			serialNum *= 997
+			if got, want := ExplainCheckDigit(ownerCode, 'U', serialNum).Remainder, CalcCheckDigit(ownerCode, 'U', serialNum); got != want {
+				t.Fatalf("ExplainCheckDigit(%s, U, %d).Remainder = %d, want %d", ownerCode, serialNum, got, want)
+			}
+		}
+	}
+}
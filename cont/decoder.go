@@ -0,0 +1,32 @@
+package cont
+
+// OwnerDecoder decodes a code to an owner.
+type OwnerDecoder interface {
+	Decode(code string) (bool, Owner)
+}
+
+// OwnerBlocker is implemented by an OwnerDecoder that blocks owner codes.
+// A blocked owner code, e.g. a retired one, fails validation with ErrOwnerBlocked.
+type OwnerBlocker interface {
+	Blocked(code string) (bool, string)
+}
+
+// EquipCatDecoder decodes an ID to an equipment category.
+type EquipCatDecoder interface {
+	Decode(ID string) (bool, EquipCat)
+}
+
+// LengthDecoder decodes a code to a length.
+type LengthDecoder interface {
+	Decode(code string) (bool, Length)
+}
+
+// HeightWidthDecoder decodes a code to height and width.
+type HeightWidthDecoder interface {
+	Decode(code string) (bool, Height, Width)
+}
+
+// TypeDecoder decodes a code to type and group information.
+type TypeDecoder interface {
+	Decode(code string) (bool, TypeInfo, GroupInfo)
+}
@@ -0,0 +1,83 @@
+package cont
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Extractor finds container numbers in arbitrary text like bills of lading, emails or manifests.
+type Extractor struct {
+	re *regexp.Regexp
+}
+
+// NewExtractor returns an Extractor that finds container numbers with upper case letters.
+// Between owner code, equipment category ID, serial number and check digit any of the
+// separators seps or no separator is tolerated.
+func NewExtractor(seps ...string) *Extractor {
+	var quotedSeps []string
+	for _, sep := range seps {
+		if sep == "" {
+			continue
+		}
+		quoted := regexp.QuoteMeta(sep)
+		if !slices.Contains(quotedSeps, quoted) {
+			quotedSeps = append(quotedSeps, quoted)
+		}
+	}
+	// longer separators first, because the leftmost alternative is preferred
+	slices.SortFunc(quotedSeps, func(a, b string) int { return len(b) - len(a) })
+
+	sep := ""
+	if len(quotedSeps) > 0 {
+		sep = "(?:" + strings.Join(quotedSeps, "|") + ")?"
+	}
+	return &Extractor{
+		re: regexp.MustCompile(`\b([A-Z]{3})` + sep + `([A-Z])` + sep + `(\d{6})` + sep + `(\d)\b`),
+	}
+}
+
+// Extracted is a container number found in a line of text.
+type Extracted struct {
+	Number Number
+	// Text is the container number as found in the line including separators.
+	Text string
+	// Column is the position of the first character in the line starting with 1.
+	Column int
+	// Start and End are the byte offsets of Text in the line, so Text is line[Start:End].
+	Start int
+	End   int
+	// CalcCheckDigit is the calculated check digit which can be 10.
+	CalcCheckDigit int
+}
+
+// Valid returns true if the check digit is the calculated check digit.
+func (e Extracted) Valid() bool {
+	return e.CalcCheckDigit%10 == e.Number.CheckDigit
+}
+
+// Extract returns all container numbers found in line in order of appearance.
+func (e *Extractor) Extract(line string) []Extracted {
+	var extracted []Extracted
+	for _, m := range e.re.FindAllStringSubmatchIndex(line, -1) {
+		serialNum, _ := strconv.Atoi(line[m[6]:m[7]])
+		checkDigit, _ := strconv.Atoi(line[m[8]:m[9]])
+		n := Number{
+			OwnerCode:    line[m[2]:m[3]],
+			EquipCatID:   rune(line[m[4]]),
+			SerialNumber: serialNum,
+			CheckDigit:   checkDigit,
+		}
+		extracted = append(extracted, Extracted{
+			Number:         n,
+			Text:           line[m[0]:m[1]],
+			Column:         utf8.RuneCountInString(line[:m[0]]) + 1,
+			Start:          m[0],
+			End:            m[1],
+			CalcCheckDigit: CalcCheckDigit(n.OwnerCode, n.EquipCatID, n.SerialNumber),
+		})
+	}
+	return extracted
+}
@@ -0,0 +1,79 @@
+package cont
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	tests := []struct {
+		name string
+		seps []string
+		line string
+		want []Extracted
+	}{
+		{
+			"Extract container numbers from prose",
+			[]string{" "},
+			"Please load ABCU 123456 0 and XYZU1234561 on vessel.",
+			[]Extracted{
+				{Number{"ABC", 'U', 123456, 0}, "ABCU 123456 0", 13, 12, 25, 0},
+				{Number{"XYZ", 'U', 123456, 1}, "XYZU1234561", 31, 30, 41, 0},
+			},
+		},
+		{
+			"Extract with multiple separators",
+			[]string{" ", "-", "- "},
+			"ABC-U 123456- 0",
+			[]Extracted{
+				{Number{"ABC", 'U', 123456, 0}, "ABC-U 123456- 0", 1, 0, 15, 0},
+			},
+		},
+		{
+			"Column counts runes",
+			[]string{" "},
+			"Container – ABCU1234560",
+			[]Extracted{
+				{Number{"ABC", 'U', 123456, 0}, "ABCU1234560", 13, 14, 25, 0},
+			},
+		},
+		{
+			"Do not extract from longer words and numbers",
+			[]string{" "},
+			"XABCU1234560 ABCU12345601 abcu1234560 ABCU 123456  0",
+			nil,
+		},
+		{
+			"Do not tolerate separators not configured",
+			[]string{""},
+			"ABC U 123456 0",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewExtractor(tt.seps...).Extract(tt.line); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtracted_Valid(t *testing.T) {
+	tests := []struct {
+		name      string
+		extracted Extracted
+		want      bool
+	}{
+		{"Valid check digit", Extracted{Number: Number{CheckDigit: 0}, CalcCheckDigit: 0}, true},
+		{"Check digit 10 is 0", Extracted{Number: Number{CheckDigit: 0}, CalcCheckDigit: 10}, true},
+		{"Invalid check digit", Extracted{Number: Number{CheckDigit: 1}, CalcCheckDigit: 3}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.extracted.Valid(); got != tt.want {
+				t.Errorf("Valid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,7 +3,9 @@ package cont
 import (
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"math/rand/v2"
+	"slices"
 )
 
 // GeneratorBuilder is the struct for the builder.
@@ -11,11 +13,38 @@ import (
 type GeneratorBuilder struct {
 	rand                        *rand.Rand
 	codes                       []string
+	equipCatID                  rune
+	sizeTypeCodes               []string
+	sizeTypeWeights             []int
 	count                       int
+	countIsSet                  bool
 	start                       int
 	end                         int
 	exclCheckDigit10            bool
 	exclErrorProneSerialNumbers bool
+	excluded                    map[ownerEquipCat]map[int]bool
+	state                       *GeneratorState
+}
+
+// GeneratorState is the state of a UniqueGenerator. A generator built with the
+// state of another generator continues generation without repeating container numbers.
+type GeneratorState struct {
+	// OwnerCodes are the owner codes in order of generation.
+	OwnerCodes  []string `json:"owner-codes"`
+	OwnerOffset int      `json:"owner-offset"`
+	EquipCatID  string   `json:"equipment-category-id"`
+	// SizeTypeHash is the hash of the size and type codes and their weights.
+	SizeTypeHash string `json:"size-type-hash,omitempty"`
+	// SizeTypeRand is the binary state of the pseudo random generator of size and type codes.
+	SizeTypeRand []byte `json:"size-type-rand,omitempty"`
+	// Random is true for random serial numbers and false for sequential serial numbers.
+	Random     bool `json:"random"`
+	RandOffset int  `json:"rand-offset"`
+	Start      int  `json:"start"`
+	// Position is the position of the serial number iterator.
+	Position int `json:"position"`
+	// Generated is the count of all generated container numbers.
+	Generated int `json:"generated"`
 }
 
 // NewUniqueGeneratorBuilder returns a new random unique container number generator.
@@ -23,10 +52,11 @@ type GeneratorBuilder struct {
 // no owner codes are passed then nil and error is returned.
 func NewUniqueGeneratorBuilder(rand *rand.Rand) *GeneratorBuilder {
 	return &GeneratorBuilder{
-		rand:  rand,
-		count: 1,
-		start: -1,
-		end:   -1,
+		rand:       rand,
+		equipCatID: 'U',
+		count:      1,
+		start:      -1,
+		end:        -1,
 	}
 }
 
@@ -36,9 +66,27 @@ func (gb *GeneratorBuilder) OwnerCodes(codes []string) *GeneratorBuilder {
 	return gb
 }
 
-// Count sets the count of container number.
+// EquipCatID sets the equipment category ID for generation. Default is U.
+func (gb *GeneratorBuilder) EquipCatID(id rune) *GeneratorBuilder {
+	gb.equipCatID = id
+	return gb
+}
+
+// SizeTypeCodes sets the size and type codes (e.g. 22G1) for generation. Every
+// container number gets a random size and type code of codes. A code is picked
+// with a probability proportional to its weight. With nil weights every code has
+// the same probability.
+func (gb *GeneratorBuilder) SizeTypeCodes(codes []string, weights []int) *GeneratorBuilder {
+	gb.sizeTypeCodes = codes
+	gb.sizeTypeWeights = weights
+	return gb
+}
+
+// Count sets the count of container number. If start and end are set and count is
+// not set, all available container numbers of the serial number range are generated.
 func (gb *GeneratorBuilder) Count(count int) *GeneratorBuilder {
 	gb.count = count
+	gb.countIsSet = true
 	return gb
 }
 
@@ -66,6 +114,35 @@ func (gb *GeneratorBuilder) ExcludeErrorProneSerialNumbers(exclude bool) *Genera
 	return gb
 }
 
+// ownerEquipCat is the owner code and equipment category ID of excluded container numbers.
+type ownerEquipCat struct {
+	code       string
+	equipCatID rune
+}
+
+// Exclude sets container numbers to exclude from generation. A container number is
+// excluded if it has the owner code, equipment category ID and serial number of an
+// excluded container number.
+func (gb *GeneratorBuilder) Exclude(numbers []Number) *GeneratorBuilder {
+	gb.excluded = make(map[ownerEquipCat]map[int]bool)
+	for _, n := range numbers {
+		key := ownerEquipCat{n.OwnerCode, n.EquipCatID}
+		if gb.excluded[key] == nil {
+			gb.excluded[key] = make(map[int]bool)
+		}
+		gb.excluded[key][n.SerialNumber] = true
+	}
+	return gb
+}
+
+// State sets the state of a previous generator to continue its generation. The owner
+// codes, equipment category ID and size and type codes with their weights must be the
+// ones of the previous generator. Start and end must not be set.
+func (gb *GeneratorBuilder) State(state *GeneratorState) *GeneratorBuilder {
+	gb.state = state
+	return gb
+}
+
 // Build returns a new UniqueGenerator if all requirements are met.
 func (gb *GeneratorBuilder) Build() (*UniqueGenerator, error) {
 	if gb.count < 1 {
@@ -78,29 +155,45 @@ func (gb *GeneratorBuilder) Build() (*UniqueGenerator, error) {
 		return nil, errors.New("cannot generate container numbers without owner codes")
 	}
 
-	serialNums := 1000000
-
-	if gb.exclCheckDigit10 {
-		serialNums = 909091
+	if err := IsEquipCatID(string(gb.equipCatID)); err != nil {
+		return nil, err
 	}
 
-	if gb.count > lenCodes*serialNums {
-		return nil, fmt.Errorf("count %d exceeds limit of %d (%d owners * %d serial numbers)",
-			gb.count, lenCodes*serialNums, lenCodes, serialNums)
+	cumWeights, err := cumulativeWeights(gb.sizeTypeCodes, gb.sizeTypeWeights)
+	if err != nil {
+		return nil, err
 	}
 
-	var sni serialNumIt
-	var count int
+	if gb.state != nil {
+		return gb.buildFromState(cumWeights)
+	}
 
+	limit := lenCodes * 1000000
 	startIsSet := gb.start > -1
 	endIsSet := gb.end > -1
 
+	// A range of serial numbers is checked after shuffling the codes, because
+	// the owner codes of the serial numbers in the range depend on their order.
+	if !endIsSet {
+		if err := gb.checkLimit(gb.count); err != nil {
+			return nil, err
+		}
+	}
+
+	var sni serialNumIt
+	var count int
+	positions := limit
+
 	switch {
 	case startIsSet && endIsSet:
 		sni = newSeqSerialNumIt(gb.start)
-		count = gb.end + 1 - gb.start
+		positions = gb.end + 1 - gb.start
 		if gb.start > gb.end {
-			count += 1000000
+			positions += 1000000
+		}
+		count = positions
+		if gb.countIsSet {
+			count = gb.count
 		}
 	case startIsSet && !endIsSet:
 		sni = newSeqSerialNumIt(gb.start)
@@ -108,66 +201,247 @@ func (gb *GeneratorBuilder) Build() (*UniqueGenerator, error) {
 	case !startIsSet && endIsSet:
 		sni = newSeqSerialNumIt(gb.end + 1 - gb.count)
 		count = gb.count
+		positions = count
 	case !startIsSet && !endIsSet:
-		sni = newRandSerialNumIt(gb.rand.Int())
+		// Offset is calculated with Uint64 instead of Int to be independent of the size of int.
+		sni = newRandSerialNumIt(int((gb.rand.Uint64() << 1 >> 1) % 1000000))
 		count = gb.count
 	}
 
+	// Codes are sorted before shuffling so that the same seed of rand results in
+	// the same container numbers regardless of the order of codes.
+	codes := slices.Sorted(slices.Values(gb.codes))
 	gb.rand.Shuffle(lenCodes, func(i, j int) {
-		gb.codes[i], gb.codes[j] = gb.codes[j], gb.codes[i]
+		codes[i], codes[j] = codes[j], codes[i]
 	})
 
+	// Without count all available container numbers of a range are generated.
+	if endIsSet && (gb.countIsSet || !startIsSet) {
+		if err := gb.checkRange(codes, sni.num(), positions, count); err != nil {
+			return nil, err
+		}
+	}
+
+	// Size and type codes have their own pseudo random generator, so its state can be
+	// saved to continue the same sequence of size and type codes.
+	var sizeTypeSrc *rand.PCG
+	if cumWeights != nil {
+		sizeTypeSrc = rand.NewPCG(gb.rand.Uint64(), gb.rand.Uint64())
+	}
+
 	return &UniqueGenerator{
-		codes:                       gb.codes,
+		codes:                       codes,
 		lenCodes:                    lenCodes,
+		equipCatID:                  gb.equipCatID,
+		sizeTypeCodes:               gb.sizeTypeCodes,
+		sizeTypeCumWeights:          cumWeights,
+		sizeTypeSrc:                 sizeTypeSrc,
+		sizeTypeRand:                newRand(sizeTypeSrc),
 		serialNumIt:                 sni,
 		count:                       count,
+		positions:                   positions,
+		exclCheckDigit10:            gb.exclCheckDigit10,
+		exclErrorProneSerialNumbers: gb.exclErrorProneSerialNumbers,
+		excluded:                    gb.excluded,
+	}, nil
+}
+
+// checkLimit returns an error if less than count container numbers can be generated.
+// With exclusions the available container numbers are counted until count is reached.
+func (gb *GeneratorBuilder) checkLimit(count int) error {
+	lenCodes := len(gb.codes)
+	limit := lenCodes * 1000000
+	if count > limit {
+		return fmt.Errorf("count %d exceeds limit of %d (%d owners * %d serial numbers)",
+			count, limit, lenCodes, 1000000)
+	}
+	if !gb.exclCheckDigit10 && !gb.exclErrorProneSerialNumbers && len(gb.excluded) == 0 {
+		return nil
+	}
+	available := 0
+	for _, code := range gb.codes {
+		for serialNum := range 1000000 {
+			if _, ok := isAvailable(code, gb.equipCatID, serialNum,
+				gb.exclCheckDigit10, gb.exclErrorProneSerialNumbers, gb.excluded); ok {
+				available++
+				if available == count {
+					return nil
+				}
+			}
+		}
+	}
+	return fmt.Errorf("count %d exceeds limit of %d (%d owners * %d serial numbers without excluded ones)",
+		count, available, lenCodes, 1000000)
+}
+
+// checkRange returns an error if less than count container numbers can be generated
+// in the positions of the sequential serial numbers from start to the end of the range.
+// With exclusions the available container numbers are counted until count is reached.
+func (gb *GeneratorBuilder) checkRange(codes []string, start, positions, count int) error {
+	available := positions
+	if gb.exclCheckDigit10 || gb.exclErrorProneSerialNumbers || len(gb.excluded) > 0 {
+		available = 0
+		sni := newSeqSerialNumIt(start)
+		ownerOffset := 0
+		for range positions {
+			serialNum := sni.num()
+			code := codes[(serialNum+ownerOffset)%len(codes)]
+			if sni.isLast() {
+				ownerOffset++
+			}
+			sni.increment()
+			if _, ok := isAvailable(code, gb.equipCatID, serialNum,
+				gb.exclCheckDigit10, gb.exclErrorProneSerialNumbers, gb.excluded); ok {
+				available++
+				if available == count {
+					return nil
+				}
+			}
+		}
+	}
+	if count > available {
+		return fmt.Errorf("count %d exceeds %d available container numbers in serial number range from %06d to %06d by %d",
+			count, available, start, gb.end, count-available)
+	}
+	return nil
+}
+
+// isAvailable returns the check digit and true if a container number is not excluded.
+func isAvailable(code string, equipCatID rune, serialNum int,
+	exclCheckDigit10, exclErrorProneSerialNumbers bool, excluded map[ownerEquipCat]map[int]bool,
+) (int, bool) {
+	if excluded[ownerEquipCat{code, equipCatID}][serialNum] {
+		return 0, false
+	}
+	checkDigit := CalcCheckDigit(code, equipCatID, serialNum)
+	if exclCheckDigit10 && checkDigit == 10 {
+		return 0, false
+	}
+	if exclErrorProneSerialNumbers && CheckTransposition(code, equipCatID, serialNum, checkDigit) != nil {
+		return 0, false
+	}
+	return checkDigit, true
+}
+
+// buildFromState returns a new UniqueGenerator that continues generation of the state.
+func (gb *GeneratorBuilder) buildFromState(cumWeights []int) (*UniqueGenerator, error) {
+	state := gb.state
+	if gb.start > -1 || gb.end > -1 {
+		return nil, errors.New("cannot set start or end of serial number range with a state")
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(gb.codes)), slices.Sorted(slices.Values(state.OwnerCodes))) {
+		return nil, errors.New("owner codes differ from owner codes of the state")
+	}
+	if state.EquipCatID != string(gb.equipCatID) {
+		return nil, errors.New("equipment category ID differs from equipment category ID of the state")
+	}
+	if sizeTypeHash(gb.sizeTypeCodes, cumWeights) != state.SizeTypeHash {
+		return nil, errors.New("size and type codes differ from size and type codes of the state")
+	}
+	if state.Position < 0 || state.Start < 0 || state.Start > 999999 ||
+		state.RandOffset < 0 || state.RandOffset > 999999 || state.Generated < 0 {
+		return nil, errors.New("state is invalid")
+	}
+	if err := gb.checkLimit(state.Generated + gb.count); err != nil {
+		return nil, fmt.Errorf("%w with %d generated container numbers of state", err, state.Generated)
+	}
+
+	var sizeTypeSrc *rand.PCG
+	if cumWeights != nil {
+		sizeTypeSrc = &rand.PCG{}
+		if err := sizeTypeSrc.UnmarshalBinary(state.SizeTypeRand); err != nil {
+			return nil, errors.New("state is invalid")
+		}
+	}
+
+	var sni serialNumIt
+	// traversed is the count of serial numbers the iterator of the state has traversed.
+	var traversed int
+	if state.Random {
+		sni = &randSerialNumIt{randOffset: state.RandOffset, it: state.Position}
+		traversed = state.Position
+	} else {
+		if state.Position > 999999 {
+			return nil, errors.New("state is invalid")
+		}
+		sni = &seqSerialNumIt{start: state.Start, it: state.Position}
+		traversed = state.OwnerOffset*1000000 + (state.Position-state.Start+1000000)%1000000
+	}
+
+	return &UniqueGenerator{
+		codes:                       slices.Clone(state.OwnerCodes),
+		lenCodes:                    len(state.OwnerCodes),
+		equipCatID:                  gb.equipCatID,
+		sizeTypeCodes:               gb.sizeTypeCodes,
+		sizeTypeCumWeights:          cumWeights,
+		sizeTypeSrc:                 sizeTypeSrc,
+		sizeTypeRand:                newRand(sizeTypeSrc),
+		ownerOffset:                 state.OwnerOffset,
+		serialNumIt:                 sni,
+		count:                       gb.count,
+		positions:                   max(len(state.OwnerCodes)*1000000-traversed, 0),
+		prevGeneratedCount:          state.Generated,
 		exclCheckDigit10:            gb.exclCheckDigit10,
 		exclErrorProneSerialNumbers: gb.exclErrorProneSerialNumbers,
+		excluded:                    gb.excluded,
 	}, nil
 }
 
 // UniqueGenerator holds state for generating random unique container numbers.
-// Use NewUniqueGeneratorBuilder for initialization.
+// Use NewUniqueGeneratorBuilder for initialization. Generation stops after the
+// remaining positions of the serial number iterator even if count is not reached.
 type UniqueGenerator struct {
 	codes                       []string
 	lenCodes                    int
+	equipCatID                  rune
+	sizeTypeCodes               []string
+	sizeTypeCumWeights          []int
+	sizeTypeSrc                 *rand.PCG
+	sizeTypeRand                *rand.Rand
+	sizeTypeCode                string
 	ownerOffset                 int
 	serialNumIt                 serialNumIt
 	count                       int
+	positions                   int
 	contNum                     Number
 	generatedCount              int
+	prevGeneratedCount          int
 	exclCheckDigit10            bool
 	exclErrorProneSerialNumbers bool
+	excluded                    map[ownerEquipCat]map[int]bool
 }
 
 // Generate advances the serial number iterator to the next serial number,
 // which will then be available through the ContNum method. It returns false
-// when the generation stops by reaching the count of generated container numbers.
+// when the generation stops by reaching the count of generated container numbers
+// or the end of the serial number range.
 func (g *UniqueGenerator) Generate() bool {
-	if g.generatedCount == g.count {
-		return false
-	}
+	for g.generatedCount < g.count && g.positions > 0 {
+		serialNum := g.serialNumIt.num()
+		code := g.codes[(serialNum+g.ownerOffset)%g.lenCodes]
 
-	serialNum := g.serialNumIt.num()
-	code := g.codes[(serialNum+g.ownerOffset)%g.lenCodes]
-	checkDigit := CalcCheckDigit(code, 'U', serialNum)
+		if g.serialNumIt.isLast() {
+			g.ownerOffset++
+		}
+		g.serialNumIt.increment()
+		g.positions--
 
-	if g.serialNumIt.isLast() {
-		g.ownerOffset++
-	}
-	g.serialNumIt.increment()
+		checkDigit, ok := isAvailable(code, g.equipCatID, serialNum,
+			g.exclCheckDigit10, g.exclErrorProneSerialNumbers, g.excluded)
+		if !ok {
+			continue
+		}
+		g.contNum = Number{code, g.equipCatID, serialNum, checkDigit % 10}
+		if len(g.sizeTypeCodes) > 0 {
+			total := g.sizeTypeCumWeights[len(g.sizeTypeCumWeights)-1]
+			i, _ := slices.BinarySearch(g.sizeTypeCumWeights, g.sizeTypeRand.IntN(total)+1)
+			g.sizeTypeCode = g.sizeTypeCodes[i]
+		}
+		g.generatedCount++
 
-	if g.exclCheckDigit10 && checkDigit == 10 {
-		return g.Generate()
+		return true
 	}
-	if g.exclErrorProneSerialNumbers && CheckTransposition(code, 'U', serialNum, checkDigit) != nil {
-		return g.Generate()
-	}
-	g.contNum = Number{code, 'U', serialNum, checkDigit % 10}
-	g.generatedCount++
-
-	return true
+	return false
 }
 
 // ContNum returns a generated container number.
@@ -175,6 +449,89 @@ func (g *UniqueGenerator) ContNum() Number {
 	return g.contNum
 }
 
+// State returns the state of the generator to continue generation with another generator.
+func (g *UniqueGenerator) State() GeneratorState {
+	state := GeneratorState{
+		OwnerCodes:   slices.Clone(g.codes),
+		OwnerOffset:  g.ownerOffset,
+		EquipCatID:   string(g.equipCatID),
+		SizeTypeHash: sizeTypeHash(g.sizeTypeCodes, g.sizeTypeCumWeights),
+		Generated:    g.prevGeneratedCount + g.generatedCount,
+	}
+	if g.sizeTypeSrc != nil {
+		// MarshalBinary of PCG never returns an error.
+		state.SizeTypeRand, _ = g.sizeTypeSrc.MarshalBinary()
+	}
+	switch sni := g.serialNumIt.(type) {
+	case *randSerialNumIt:
+		state.Random = true
+		state.RandOffset = sni.randOffset
+		state.Position = sni.it
+	case *seqSerialNumIt:
+		state.Start = sni.start
+		state.Position = sni.it
+	}
+	return state
+}
+
+// SizeTypeCode returns the size and type code of a generated container number.
+// It is empty if no size and type codes are set.
+func (g *UniqueGenerator) SizeTypeCode() string {
+	return g.sizeTypeCode
+}
+
+// newRand returns a new rand.Rand of src or nil if src is nil.
+func newRand(src *rand.PCG) *rand.Rand {
+	if src == nil {
+		return nil
+	}
+	return rand.New(src)
+}
+
+// sizeTypeHash returns the FNV-1a hash of size and type codes and their cumulative
+// weights in hexadecimal or an empty string without codes.
+func sizeTypeHash(codes []string, cumWeights []int) string {
+	if len(codes) == 0 {
+		return ""
+	}
+	h := fnv.New64a()
+	for i, code := range codes {
+		_, _ = fmt.Fprintf(h, "%s:%d;", code, cumWeights[i])
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// cumulativeWeights returns the cumulative weights of size and type codes. With
+// nil weights every code has the weight 1.
+func cumulativeWeights(codes []string, weights []int) ([]int, error) {
+	if weights != nil && len(weights) != len(codes) {
+		return nil, fmt.Errorf("%d weights do not match %d size and type codes", len(weights), len(codes))
+	}
+	if len(codes) == 0 {
+		return nil, nil
+	}
+	cumWeights := make([]int, len(codes))
+	total := 0
+	for i, code := range codes {
+		if err := IsSizeTypeCode(code); err != nil {
+			return nil, err
+		}
+		weight := 1
+		if weights != nil {
+			weight = weights[i]
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("weight %d of size and type code %s is negative", weight, code)
+		}
+		total += weight
+		cumWeights[i] = total
+	}
+	if total == 0 {
+		return nil, errors.New("cannot generate size and type codes with a total weight of 0")
+	}
+	return cumWeights, nil
+}
+
 type serialNumIt interface {
 	num() int
 
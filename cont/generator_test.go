@@ -2,8 +2,10 @@ package cont
 
 import (
 	"fmt"
+	"maps"
 	"math/rand/v2"
 	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -31,12 +33,14 @@ func TestGeneratorBuilder(t *testing.T) {
 				true,
 			},
 			&UniqueGenerator{
-				codes:    []string{"ABC"},
-				lenCodes: 1,
+				codes:      []string{"ABC"},
+				lenCodes:   1,
+				equipCatID: 'U',
 				serialNumIt: &randSerialNumIt{
-					randOffset: 1812594575390091523,
+					randOffset: 91523,
 				},
 				count:            2,
+				positions:        1000000,
 				exclCheckDigit10: true,
 			},
 			false,
@@ -52,8 +56,10 @@ func TestGeneratorBuilder(t *testing.T) {
 			&UniqueGenerator{
 				codes:       []string{"ABC"},
 				lenCodes:    1,
+				equipCatID:  'U',
 				serialNumIt: newSeqSerialNumIt(2),
 				count:       3,
+				positions:   1000000,
 			},
 			false,
 		},
@@ -68,8 +74,10 @@ func TestGeneratorBuilder(t *testing.T) {
 			&UniqueGenerator{
 				codes:       []string{"ABC"},
 				lenCodes:    1,
+				equipCatID:  'U',
 				serialNumIt: newSeqSerialNumIt(-1),
 				count:       4,
+				positions:   4,
 			},
 			false,
 		},
@@ -84,8 +92,10 @@ func TestGeneratorBuilder(t *testing.T) {
 			&UniqueGenerator{
 				codes:       []string{"ABC"},
 				lenCodes:    1,
+				equipCatID:  'U',
 				serialNumIt: newSeqSerialNumIt(2),
-				count:       4,
+				count:       1,
+				positions:   4,
 			},
 			false,
 		},
@@ -162,7 +172,7 @@ func TestUniqueGenerator(t *testing.T) {
 			"Generate 1 container number",
 			NewUniqueGeneratorBuilder(r).
 				OwnerCodes([]string{"ABC"}).
-				Count(3).
+				Count(1).
 				Start(1).
 				End(1),
 			true,
@@ -262,3 +272,239 @@ func TestUniqueGenerator(t *testing.T) {
 		})
 	}
 }
+
+func TestUniqueGeneratorEquipCatIDAndSizeTypeCodes(t *testing.T) {
+	tests := []struct {
+		name          string
+		equipCatID    rune
+		codes         []string
+		weights       []int
+		wantSizeTypes []string
+		wantErr       bool
+	}{
+		{"Generate without size and type codes", 'J', nil, nil, []string{""}, false},
+		{"Generate fixed size and type code", 'Z', []string{"22G1"}, nil, []string{"22G1"}, false},
+		{"Generate size and type codes with same weight", 'U', []string{"22G1", "45R1"}, nil, []string{"22G1", "45R1"}, false},
+		{"Generate size and type codes with weights", 'U', []string{"22G1", "45R1", "L5G1"}, []int{0, 3, 0}, []string{"45R1"}, false},
+		{"Return error for invalid equipment category ID", 'u', nil, nil, nil, true},
+		{"Return error for invalid size and type code", 'U', []string{"22G"}, nil, nil, true},
+		{"Return error for not matching weights", 'U', []string{"22G1"}, []int{1, 2}, nil, true},
+		{"Return error for negative weight", 'U', []string{"22G1"}, []int{-1}, nil, true},
+		{"Return error for total weight 0", 'U', []string{"22G1"}, []int{0}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).
+				OwnerCodes([]string{"ABC"}).
+				Count(100).
+				EquipCatID(tt.equipCatID).
+				SizeTypeCodes(tt.codes, tt.weights).
+				Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GeneratorBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			sizeTypes := map[string]bool{}
+			for g.Generate() {
+				cn := g.ContNum()
+				if cn.EquipCatID != tt.equipCatID {
+					t.Errorf("UniqueGenerator.ContNum() equipment category ID = %c, want %c", cn.EquipCatID, tt.equipCatID)
+				}
+				if checkDigit := CalcCheckDigit(cn.OwnerCode, cn.EquipCatID, cn.SerialNumber) % 10; checkDigit != cn.CheckDigit {
+					t.Errorf("UniqueGenerator.ContNum() check digit = %d, want %d", cn.CheckDigit, checkDigit)
+				}
+				sizeTypes[g.SizeTypeCode()] = true
+			}
+			got := slices.Sorted(maps.Keys(sizeTypes))
+			if !reflect.DeepEqual(got, tt.wantSizeTypes) {
+				t.Errorf("UniqueGenerator.SizeTypeCode() = %v, want %v", got, tt.wantSizeTypes)
+			}
+		})
+	}
+}
+
+func TestUniqueGeneratorSeed(t *testing.T) {
+	generate := func(codes []string) []string {
+		g, err := NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(42, 0))).
+			OwnerCodes(codes).
+			Count(5).
+			SizeTypeCodes([]string{"22G1", "45R1"}, []int{2, 1}).
+			Build()
+		if err != nil {
+			t.Fatalf("GeneratorBuilder.Build() error = %v", err)
+		}
+		var numbers []string
+		for g.Generate() {
+			cn := g.ContNum()
+			numbers = append(numbers, fmt.Sprintf("%s%c%06d%d %s", cn.OwnerCode, cn.EquipCatID, cn.SerialNumber, cn.CheckDigit, g.SizeTypeCode()))
+		}
+		return numbers
+	}
+
+	want := []string{
+		"MSKU8468565 45R1",
+		"ZIMU6752241 45R1",
+		"ZIMU1603409 22G1",
+		"ABCU3022236 45R1",
+		"ABCU1009507 45R1",
+	}
+	if got := generate([]string{"ZIM", "ABC", "MSK"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("UniqueGenerator with seed 42 = %#v, want %#v", got, want)
+	}
+	if got := generate([]string{"ABC", "MSK", "ZIM"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("UniqueGenerator with seed 42 and sorted owner codes = %#v, want %#v", got, want)
+	}
+}
+
+func TestUniqueGeneratorState(t *testing.T) {
+	codes := []string{"ABC", "DEF"}
+	generate := func(gb *GeneratorBuilder) ([]string, GeneratorState) {
+		g, err := gb.Build()
+		if err != nil {
+			t.Fatalf("GeneratorBuilder.Build() error = %v", err)
+		}
+		var numbers []string
+		for g.Generate() {
+			cn := g.ContNum()
+			numbers = append(numbers, fmt.Sprintf("%s%c%06d%d%s", cn.OwnerCode, cn.EquipCatID, cn.SerialNumber, cn.CheckDigit, g.SizeTypeCode()))
+		}
+		return numbers, g.State()
+	}
+
+	tests := []struct {
+		name          string
+		start         int
+		sizeTypeCodes []string
+	}{
+		{"Continue random serial numbers", -1, nil},
+		{"Continue sequential serial numbers", 999998, nil},
+		{"Continue random size and type codes", -1, []string{"22G1", "42G1", "45R1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, _ := generate(NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).
+				OwnerCodes(codes).SizeTypeCodes(tt.sizeTypeCodes, nil).Count(10).Start(tt.start))
+
+			first, state := generate(NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).
+				OwnerCodes(codes).SizeTypeCodes(tt.sizeTypeCodes, nil).Count(4).Start(tt.start))
+			second, state := generate(NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(2, 0))).
+				OwnerCodes(codes).SizeTypeCodes(tt.sizeTypeCodes, nil).Count(6).State(&state))
+
+			if got := append(first, second...); !reflect.DeepEqual(got, want) {
+				t.Errorf("UniqueGenerator with state = %v, want %v", got, want)
+			}
+			if state.Generated != 10 {
+				t.Errorf("UniqueGenerator.State() Generated = %d, want %d", state.Generated, 10)
+			}
+		})
+	}
+}
+
+func TestGeneratorBuilderStateErrors(t *testing.T) {
+	state := GeneratorState{OwnerCodes: []string{"ABC"}, EquipCatID: "U", Random: true, RandOffset: 5, Generated: 999990}
+	tests := []struct {
+		name  string
+		gb    *GeneratorBuilder
+		state GeneratorState
+	}{
+		{"Different owner codes", NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).OwnerCodes([]string{"DEF"}), state},
+		{"Different equipment category ID", NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).OwnerCodes([]string{"ABC"}).EquipCatID('J'), state},
+		{"Different size and type codes", NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).OwnerCodes([]string{"ABC"}).SizeTypeCodes([]string{"22G1"}, nil), state},
+		{"Start with state", NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).OwnerCodes([]string{"ABC"}).Start(5), state},
+		{"Count exceeds remaining limit", NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).OwnerCodes([]string{"ABC"}).Count(11), state},
+		{"Invalid state", NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).OwnerCodes([]string{"ABC"}), GeneratorState{OwnerCodes: []string{"ABC"}, Position: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.gb.State(&tt.state).Build(); err == nil {
+				t.Errorf("GeneratorBuilder.Build() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestUniqueGeneratorExclude(t *testing.T) {
+	excluded := []Number{
+		{OwnerCode: "ABC", EquipCatID: 'U', SerialNumber: 1},
+		{OwnerCode: "ABC", EquipCatID: 'U', SerialNumber: 3},
+		{OwnerCode: "DEF", EquipCatID: 'U', SerialNumber: 2},
+	}
+	tests := []struct {
+		name    string
+		gb      *GeneratorBuilder
+		want    []string
+		wantErr bool
+	}{
+		{
+			"Skip excluded serial numbers",
+			NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).
+				OwnerCodes([]string{"ABC"}).Start(0).Count(3).Exclude(excluded),
+			[]string{"ABCU0000001", "ABCU0000022", "ABCU0000043"},
+			false,
+		},
+		{
+			"Stop at end of range",
+			NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).
+				OwnerCodes([]string{"ABC"}).Start(0).End(3).Exclude(excluded),
+			[]string{"ABCU0000001", "ABCU0000022"},
+			false,
+		},
+		{
+			"Generate count of range",
+			NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).
+				OwnerCodes([]string{"ABC"}).Start(0).End(3).Count(1).Exclude(excluded),
+			[]string{"ABCU0000001"},
+			false,
+		},
+		{
+			"Return error for count exceeding available serial numbers of range",
+			NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).
+				OwnerCodes([]string{"ABC"}).Start(0).End(3).Count(3).Exclude(excluded),
+			nil,
+			true,
+		},
+		{
+			"Return error for count exceeding available serial numbers of range with only end",
+			NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).
+				OwnerCodes([]string{"ABC"}).End(3).Count(4).Exclude(excluded),
+			nil,
+			true,
+		},
+		{
+			"Return error for count exceeding limit without excluded serial numbers",
+			NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).
+				OwnerCodes([]string{"ABC"}).Count(999999).Exclude(excluded),
+			nil,
+			true,
+		},
+		{
+			"Return error for count exceeding limit without check digit 10",
+			NewUniqueGeneratorBuilder(rand.New(rand.NewPCG(1, 0))).
+				OwnerCodes([]string{"ABC"}).Count(950000).ExcludeCheckDigit10(true),
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := tt.gb.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GeneratorBuilder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for g.Generate() {
+				got = append(got, g.ContNum().String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UniqueGenerator.Generate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
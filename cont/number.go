@@ -1,5 +1,11 @@
 package cont
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // Number is a container number with needed properties to conform to the specified standard.
 type Number struct {
 	OwnerCode    string
@@ -7,3 +13,72 @@ type Number struct {
 	SerialNumber int
 	CheckDigit   int
 }
+
+// ParseNumber parses a container number like "ABCU1234560" or "abc u 123456 0".
+// Letters are converted to upper case and non-alphanumeric characters between
+// the parts are ignored. The check digit is parsed but not verified, use
+// Validator to validate a container number. The error is a ValidateError with
+// the code and the value of the first part that cannot be parsed.
+func ParseNumber(s string) (Number, error) {
+	n, hasCheckDigit, err := ParseNumberOptionalCheckDigit(s)
+	if err != nil {
+		return Number{}, err
+	}
+	if !hasCheckDigit {
+		return Number{}, WithValue(ErrCheckDigitFormat, "", "1 number")
+	}
+	return n, nil
+}
+
+// ParseNumberOptionalCheckDigit parses a container number like ParseNumber, but the
+// check digit is optional. It returns false if the container number has no check digit.
+func ParseNumberOptionalCheckDigit(s string) (Number, bool, error) {
+	rest := strings.ToUpper(s)
+	var ownerCode, equipCatID, serialNum string
+	ownerCode, rest = nextAlphanumerics(rest, 3)
+	if IsOwnerCode(ownerCode) != nil {
+		return Number{}, false, WithValue(ErrOwnerCodeFormat, ownerCode, "3 letters")
+	}
+	equipCatID, rest = nextAlphanumerics(rest, 1)
+	if IsEquipCatID(equipCatID) != nil {
+		return Number{}, false, WithValue(ErrEquipCatIDFormat, equipCatID, "1 letter")
+	}
+	serialNum, rest = nextAlphanumerics(rest, 6)
+	if err := serialNumErr(serialNum); err != nil {
+		return Number{}, false, err
+	}
+	// The check digit is the rest, so characters after the check digit are an error.
+	checkDigit := strings.TrimFunc(rest, isSeparator)
+	if checkDigit != "" && (len(checkDigit) != 1 || !isDigits(checkDigit)) {
+		return Number{}, false, WithValue(ErrCheckDigitFormat, checkDigit, "1 number")
+	}
+	serialNumber, _ := strconv.Atoi(serialNum)
+	checkDigitNumber, _ := strconv.Atoi(checkDigit)
+	return Number{
+		OwnerCode:    ownerCode,
+		EquipCatID:   rune(equipCatID[0]),
+		SerialNumber: serialNumber,
+		CheckDigit:   checkDigitNumber,
+	}, checkDigit != "", nil
+}
+
+// nextAlphanumerics skips separators of s and returns at most n following upper case
+// letters and digits and the rest of s after them.
+func nextAlphanumerics(s string, n int) (string, string) {
+	s = strings.TrimLeftFunc(s, isSeparator)
+	i := 0
+	for i < len(s) && i < n && !isSeparator(rune(s[i])) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// isSeparator returns true for characters other than upper case letters and digits.
+func isSeparator(r rune) bool {
+	return (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+}
+
+// String returns the container number without separators, e.g. ABCU1234560.
+func (n Number) String() string {
+	return fmt.Sprintf("%s%c%06d%d", n.OwnerCode, n.EquipCatID, n.SerialNumber, n.CheckDigit)
+}
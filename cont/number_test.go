@@ -0,0 +1,126 @@
+package cont
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Number
+		wantErr bool
+	}{
+		{
+			"Parse container number without separators",
+			"ABCU1234560",
+			Number{"ABC", 'U', 123456, 0},
+			false,
+		},
+		{
+			"Parse lower case container number with separators",
+			" abc u 001234-5 ",
+			Number{"ABC", 'U', 1234, 5},
+			false,
+		},
+		{
+			"Parse container number with missing check digit",
+			"ABCU123456",
+			Number{},
+			true,
+		},
+		{
+			"Parse container number with size and type code",
+			"ABCU1234560 22G1",
+			Number{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNumber(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseNumber() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseNumber() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNumberOptionalCheckDigit(t *testing.T) {
+	tests := []struct {
+		name           string
+		s              string
+		want           Number
+		wantCheckDigit bool
+		wantErr        bool
+	}{
+		{
+			"Parse container number with check digit",
+			"abc u 123456-0",
+			Number{"ABC", 'U', 123456, 0},
+			true,
+			false,
+		},
+		{
+			"Parse container number without check digit",
+			"ABCU123456",
+			Number{"ABC", 'U', 123456, 0},
+			false,
+			false,
+		},
+		{
+			"Parse too short container number",
+			"ABCU12345",
+			Number{},
+			false,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotCheckDigit, err := ParseNumberOptionalCheckDigit(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseNumberOptionalCheckDigit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want || gotCheckDigit != tt.wantCheckDigit {
+				t.Errorf("ParseNumberOptionalCheckDigit() = %v, %v, want %v, %v", got, gotCheckDigit, tt.want, tt.wantCheckDigit)
+			}
+		})
+	}
+}
+
+func TestParseNumberErrorCodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		wantCode  ErrorCode
+		wantValue string
+	}{
+		{"Return code of owner code", "AB1U1234560", CodeOwnerCodeFormat, "AB1"},
+		{"Return code of empty owner code", "", CodeOwnerCodeFormat, ""},
+		{"Return code of equipment category ID", "ABC11234560", CodeEquipCatFormat, "1"},
+		{"Return code of serial number with letter", "ABCU12A4560", CodeSerialFormat, "12A456"},
+		{"Return code of too short serial number", "ABCU12345", CodeSerialTooShort, "12345"},
+		{"Return code of missing check digit", "ABCU123456", CodeCheckDigitFormat, ""},
+		{"Return code of characters after check digit", "ABCU1234560 22G1", CodeCheckDigitFormat, "0 22G1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseNumber(tt.s)
+			var validateErr *ValidateError
+			if !errors.As(err, &validateErr) {
+				t.Fatalf("ParseNumber() error = %v, want ValidateError", err)
+			}
+			if validateErr.Code != tt.wantCode || validateErr.Value != tt.wantValue {
+				t.Errorf("ParseNumber() code = %v, value = %v, want %v, %v",
+					validateErr.Code, validateErr.Value, tt.wantCode, tt.wantValue)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package cont
 
-import "fmt"
+import (
+	"fmt"
+	"iter"
+)
 
 // Owner has a code and associated company with its location in the form of country and city.
 type Owner struct {
@@ -20,3 +23,20 @@ func IsOwnerCode(code string) error {
 	}
 	return nil
 }
+
+// AllOwnerCodes returns all owner codes from AAA to ZZZ in alphabetical order.
+func AllOwnerCodes() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		code := []byte("AAA")
+		for code[0] <= 'Z' {
+			if !yield(string(code)) {
+				return
+			}
+			code[2]++
+			for i := 2; i > 0 && code[i] > 'Z'; i-- {
+				code[i] = 'A'
+				code[i-1]++
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package cont
+
+import (
+	"slices"
+	"strings"
+)
+
+// OwnerChange has an owner before and after a change of company, city or country.
+type OwnerChange struct {
+	Old Owner
+	New Owner
+}
+
+// OwnersDiff has the added, removed and changed owners between two lists of owners.
+// All owners are sorted by owner code.
+type OwnersDiff struct {
+	Added   []Owner
+	Removed []Owner
+	Changed []OwnerChange
+}
+
+// IsEmpty returns true if no owner is added, removed or changed.
+func (d OwnersDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffOwners returns the differences from oldOwners to newOwners by owner code.
+func DiffOwners(oldOwners, newOwners []Owner) OwnersDiff {
+	oldByCode := make(map[string]Owner, len(oldOwners))
+	for _, o := range oldOwners {
+		oldByCode[o.Code] = o
+	}
+	newByCode := make(map[string]Owner, len(newOwners))
+	for _, o := range newOwners {
+		newByCode[o.Code] = o
+	}
+
+	var diff OwnersDiff
+	for code, n := range newByCode {
+		o, ok := oldByCode[code]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, n)
+		case o != n:
+			diff.Changed = append(diff.Changed, OwnerChange{Old: o, New: n})
+		}
+	}
+	for code, o := range oldByCode {
+		if _, ok := newByCode[code]; !ok {
+			diff.Removed = append(diff.Removed, o)
+		}
+	}
+
+	byCode := func(a, b Owner) int { return strings.Compare(a.Code, b.Code) }
+	slices.SortFunc(diff.Added, byCode)
+	slices.SortFunc(diff.Removed, byCode)
+	slices.SortFunc(diff.Changed, func(a, b OwnerChange) int { return byCode(a.New, b.New) })
+	return diff
+}
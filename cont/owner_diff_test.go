@@ -0,0 +1,55 @@
+package cont
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffOwners(t *testing.T) {
+	abc := Owner{Code: "ABC", Company: "company", City: "city", Country: "country"}
+	def := Owner{Code: "DEF", Company: "company", City: "city", Country: "country"}
+	defChanged := Owner{Code: "DEF", Company: "other company", City: "city", Country: "country"}
+	ghi := Owner{Code: "GHI", Company: "company", City: "city", Country: "country"}
+	xyz := Owner{Code: "XYZ", Company: "company", City: "city", Country: "country"}
+
+	tests := []struct {
+		name      string
+		oldOwners []Owner
+		newOwners []Owner
+		want      OwnersDiff
+	}{
+		{
+			"Diff equal owners",
+			[]Owner{abc, def},
+			[]Owner{def, abc},
+			OwnersDiff{},
+		},
+		{
+			"Diff added, removed and changed owners",
+			[]Owner{xyz, abc, def},
+			[]Owner{ghi, defChanged, abc},
+			OwnersDiff{
+				Added:   []Owner{ghi},
+				Removed: []Owner{xyz},
+				Changed: []OwnerChange{{Old: def, New: defChanged}},
+			},
+		},
+		{
+			"Diff owners without old owners",
+			nil,
+			[]Owner{def, abc},
+			OwnersDiff{Added: []Owner{abc, def}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffOwners(tt.oldOwners, tt.newOwners)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffOwners() = %v, want %v", got, tt.want)
+			}
+			if got.IsEmpty() != reflect.DeepEqual(tt.want, OwnersDiff{}) {
+				t.Errorf("IsEmpty() = %v", got.IsEmpty())
+			}
+		})
+	}
+}
@@ -38,3 +38,17 @@ func IsTypeCode(code string) error {
 	}
 	return nil
 }
+
+// IsSizeTypeCode returns nil if input is a length, a height and width and a type code (e.g. 22G1).
+func IsSizeTypeCode(code string) error {
+	if len(code) != 4 {
+		return NewValidateError(fmt.Sprintf("%s is not 4 characters long", code))
+	}
+	if err := IsLengthCode(code[0:1]); err != nil {
+		return err
+	}
+	if err := IsHeightWidthCode(code[1:2]); err != nil {
+		return err
+	}
+	return IsTypeCode(code[2:4])
+}
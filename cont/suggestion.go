@@ -0,0 +1,207 @@
+package cont
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// EditKind is the kind of correction that leads from an input to a Suggestion.
+type EditKind string
+
+// Kinds of edits ordered from most to least likely.
+const (
+	EditOCRConfusion  EditKind = "ocr-confusion"
+	EditTransposition EditKind = "transposition"
+	EditSubstitution  EditKind = "substitution"
+)
+
+// editCosts are the costs of edits. A lower cost means a more likely edit.
+var editCosts = map[EditKind]int{
+	EditOCRConfusion:  1,
+	EditTransposition: 2,
+	EditSubstitution:  3,
+}
+
+// Edit is a correction at position Pos of a container number, starting with 0.
+// For a transposition Pos is the position of the first of the two transposed characters.
+type Edit struct {
+	Kind EditKind
+	Pos  int
+}
+
+// Suggestion is a valid container number that was likely intended by an invalid input.
+type Suggestion struct {
+	Number Number
+	Edits  []Edit
+	// Cost is the sum of the costs of all edits. A lower cost is more likely.
+	Cost int
+}
+
+// ocrLetters maps digits to letters that are often confused by OCR or humans.
+var ocrLetters = map[byte]byte{'0': 'O', '1': 'I', '2': 'Z', '5': 'S', '8': 'B'}
+
+// ocrDigits maps letters to digits that are often confused by OCR or humans.
+var ocrDigits = map[byte]byte{'O': '0', 'I': '1', 'Z': '2', 'S': '5', 'B': '8'}
+
+// Suggest returns valid container numbers that were likely intended by in, ranked by
+// the cost of the edits. The first 11 letters and digits of in are used as container
+// number. Characters in wrong positions that are commonly confused (O/0, I/1, B/8, S/5
+// and Z/2) are corrected and at most one further edit is applied: a substitution of a
+// character, a transposition of adjacent characters or a confusion of a character.
+// Only container numbers with a registered owner, a known equipment category ID and
+// a valid check digit are suggested. Suggest returns nil for a valid container number
+// or if no suggestion is found.
+func (v *Validator) Suggest(in string) []Suggestion {
+	var chars []byte
+	for _, r := range strings.ToUpper(in) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			chars = append(chars, byte(r))
+		}
+	}
+	if len(chars) < 11 {
+		return nil
+	}
+	chars = chars[:11]
+
+	if v.isValidNumber(chars) {
+		return nil
+	}
+
+	var ocrEdits []Edit
+	var wrongPos []int
+	for pos, c := range chars {
+		if isLetterPos(pos) == isLetter(c) {
+			continue
+		}
+		confused, ok := confusion(pos, c)
+		if !ok {
+			wrongPos = append(wrongPos, pos)
+			continue
+		}
+		chars[pos] = confused
+		ocrEdits = append(ocrEdits, Edit{EditOCRConfusion, pos})
+	}
+	// Only one further edit is applied, which can correct only one character.
+	if len(wrongPos) > 1 {
+		return nil
+	}
+
+	suggestions := map[string]Suggestion{}
+	add := func(candidate []byte, edits ...Edit) {
+		if !v.isValidNumber(candidate) {
+			return
+		}
+		// a further edit at a corrected position replaces the correction
+		allEdits := slices.DeleteFunc(slices.Clone(ocrEdits), func(ocrEdit Edit) bool {
+			return slices.ContainsFunc(edits, func(e Edit) bool {
+				return e.Kind != EditTransposition && e.Pos == ocrEdit.Pos
+			})
+		})
+		allEdits = append(allEdits, edits...)
+		cost := 0
+		for _, e := range allEdits {
+			cost += editCosts[e.Kind]
+		}
+		key := string(candidate)
+		if s, ok := suggestions[key]; ok && s.Cost <= cost {
+			return
+		}
+		n, _ := ParseNumber(key)
+		suggestions[key] = Suggestion{Number: n, Edits: allEdits, Cost: cost}
+	}
+
+	if len(wrongPos) == 0 {
+		add(chars)
+	}
+
+	for pos := range chars {
+		if len(wrongPos) == 1 && pos != wrongPos[0] {
+			continue
+		}
+		original := chars[pos]
+		candidate := slices.Clone(chars)
+
+		if confused, ok := confusion(pos, original); ok && len(wrongPos) == 0 {
+			candidate[pos] = confused
+			add(candidate, Edit{EditOCRConfusion, pos})
+		}
+		for _, c := range []byte(alphabet(pos)) {
+			if c == original {
+				continue
+			}
+			candidate[pos] = c
+			add(candidate, Edit{EditSubstitution, pos})
+		}
+	}
+
+	if len(wrongPos) == 0 {
+		for pos := 0; pos < len(chars)-1; pos++ {
+			if chars[pos] == chars[pos+1] || isLetterPos(pos) != isLetterPos(pos+1) {
+				continue
+			}
+			candidate := slices.Clone(chars)
+			candidate[pos], candidate[pos+1] = candidate[pos+1], candidate[pos]
+			add(candidate, Edit{EditTransposition, pos})
+		}
+	}
+
+	if len(suggestions) == 0 {
+		return nil
+	}
+	result := make([]Suggestion, 0, len(suggestions))
+	for _, s := range suggestions {
+		result = append(result, s)
+	}
+	slices.SortFunc(result, func(a, b Suggestion) int {
+		return cmp.Or(cmp.Compare(a.Cost, b.Cost), strings.Compare(a.Number.String(), b.Number.String()))
+	})
+	return result
+}
+
+// isValidNumber returns true if chars is a container number with a registered owner,
+// which is not blocked, a known equipment category ID and a valid check digit.
+func (v *Validator) isValidNumber(chars []byte) bool {
+	n, err := ParseNumber(string(chars))
+	if err != nil {
+		return false
+	}
+	if found, _ := v.ownerDecoder.Decode(n.OwnerCode); !found {
+		return false
+	}
+	if blocker, ok := v.ownerDecoder.(OwnerBlocker); ok {
+		if blocked, _ := blocker.Blocked(n.OwnerCode); blocked {
+			return false
+		}
+	}
+	if found, _ := v.equipCatDecoder.Decode(string(n.EquipCatID)); !found {
+		return false
+	}
+	return CalcCheckDigit(n.OwnerCode, n.EquipCatID, n.SerialNumber)%10 == n.CheckDigit
+}
+
+// confusion returns the character that c is commonly confused with at position pos.
+func confusion(pos int, c byte) (byte, bool) {
+	if isLetterPos(pos) {
+		confused, ok := ocrLetters[c]
+		return confused, ok
+	}
+	confused, ok := ocrDigits[c]
+	return confused, ok
+}
+
+func alphabet(pos int) string {
+	if isLetterPos(pos) {
+		return "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	}
+	return "0123456789"
+}
+
+// isLetterPos returns true for the positions of owner code and equipment category ID.
+func isLetterPos(pos int) bool {
+	return pos < 4
+}
+
+func isLetter(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,93 @@
+package cont
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidator_Suggest(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []Suggestion
+	}{
+		{
+			"Valid container number has no suggestions",
+			"ABC U 123456 0",
+			nil,
+		},
+		{
+			"Too short input has no suggestions",
+			"ABC U 12345",
+			nil,
+		},
+		{
+			"Two wrong characters have no suggestions",
+			"A4CU12X4560",
+			nil,
+		},
+		{
+			"Owner code with digit confused for letter",
+			"A8C U 123456 0",
+			[]Suggestion{
+				{newNumber(t, "ABCU1234560"), []Edit{{EditOCRConfusion, 1}}, 1},
+			},
+		},
+		{
+			"Transposition of serial number and check digit",
+			"ABC U 123450 6",
+			[]Suggestion{
+				{newNumber(t, "ABCU1234560"), []Edit{{EditTransposition, 9}}, 2},
+				{newNumber(t, "ABCU1234508"), []Edit{{EditSubstitution, 10}}, 3},
+			},
+		},
+		{
+			"Substitution replaces confusion at same position",
+			"abc u 12345b 0",
+			[]Suggestion{
+				{newNumber(t, "ABCU1234508"), []Edit{{EditOCRConfusion, 9}, {EditTransposition, 9}}, 3},
+				{newNumber(t, "ABCU1234540"), []Edit{{EditSubstitution, 9}}, 3},
+				{newNumber(t, "ABCU1234560"), []Edit{{EditSubstitution, 9}}, 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newDummyValidator().Suggest(tt.in)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("Suggest() = %v, want nil", got)
+				}
+				return
+			}
+			if len(got) < len(tt.want) {
+				t.Fatalf("Suggest() = %v, want at least %d suggestions", got, len(tt.want))
+			}
+			if !reflect.DeepEqual(got[:len(tt.want)], tt.want) {
+				t.Errorf("Suggest() = %v, want first %v", got[:len(tt.want)], tt.want)
+			}
+		})
+	}
+}
+
+func newNumber(t *testing.T, s string) Number {
+	t.Helper()
+	n, err := ParseNumber(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestValidator_SuggestBlockedOwner(t *testing.T) {
+	validator := NewValidator(
+		blockingOwnerDecoder{},
+		dummyEquipCatDecoder{},
+		dummyLengthDecoder{},
+		dummyHeightWidthDecoder{},
+		dummyTypeDecoder{},
+	)
+	if got := validator.Suggest("A8C U 123456 0"); got != nil {
+		t.Errorf("Suggest() = %v, want no suggestions of blocked owner", got)
+	}
+}
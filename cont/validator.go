@@ -1,10 +1,63 @@
 package cont
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrorCode is a stable code of a validation error. Unlike error messages,
+// codes do not change and can be used by other systems to handle errors.
+type ErrorCode string
+
+// Codes of the errors of a Validator.
+const (
+	CodeOwnerCodeFormat         ErrorCode = "OWNER_CODE_FORMAT"
+	CodeOwnerNotRegistered      ErrorCode = "OWNER_NOT_REGISTERED"
+	CodeOwnerBlocked            ErrorCode = "OWNER_BLOCKED"
+	CodeEquipCatFormat          ErrorCode = "EQUIP_CAT_FORMAT"
+	CodeEquipCatUnknown         ErrorCode = "EQUIP_CAT_UNKNOWN"
+	CodeSerialFormat            ErrorCode = "SERIAL_FORMAT"
+	CodeSerialTooShort          ErrorCode = "SERIAL_TOO_SHORT"
+	CodeCheckDigitNotCalculable ErrorCode = "CHECK_DIGIT_NOT_CALCULABLE"
+	CodeCheckDigitFormat        ErrorCode = "CHECK_DIGIT_FORMAT"
+	CodeCheckDigitMismatch      ErrorCode = "CHECK_DIGIT_MISMATCH"
+	CodeLengthCodeFormat        ErrorCode = "LENGTH_CODE_FORMAT"
+	CodeLengthCodeUnknown       ErrorCode = "LENGTH_CODE_UNKNOWN"
+	CodeHeightWidthCodeFormat   ErrorCode = "HEIGHT_WIDTH_CODE_FORMAT"
+	CodeHeightWidthCodeUnknown  ErrorCode = "HEIGHT_WIDTH_CODE_UNKNOWN"
+	CodeTypeCodeFormat          ErrorCode = "TYPE_CODE_FORMAT"
+	CodeTypeCodeUnknown         ErrorCode = "TYPE_CODE_UNKNOWN"
+)
+
+// Names of the validated parts of a ValidateError.
+const (
+	PartOwnerCode       = "owner-code"
+	PartEquipCatID      = "equipment-category-id"
+	PartSerialNum       = "serial-number"
+	PartCheckDigit      = "check-digit"
+	PartLengthCode      = "length-code"
+	PartHeightWidthCode = "height-width-code"
+	PartTypeCode        = "type-code"
+)
 
 // ValidateError is an error for validation of a container number part.
+// Use errors.As to get the code, the part and the values of an error.
 type ValidateError struct {
-	message string
+	// Code is the stable code of the error. Code is empty for errors
+	// returned by NewValidateError.
+	Code ErrorCode
+	// Part is the name of the validated part, e.g. owner-code.
+	Part string
+	// Value is the value of the part that is not valid.
+	Value string
+	// Expected is the expected value of the part, e.g. the calculated check digit,
+	// or a description of it. Expected is empty if there is no expected value.
+	Expected string
+	message  string
+	err      error
 }
 
 // NewValidateError returns a new ValidateError.
@@ -14,10 +67,379 @@ func NewValidateError(message string) error {
 	}
 }
 
+func newCodeError(code ErrorCode, part, message string) error {
+	return &ValidateError{
+		Code:    code,
+		Part:    part,
+		message: message,
+	}
+}
+
+// WithValue returns a copy of err with the value of the part and the expected value.
+// The copy wraps err, so errors.Is reports a match for err. If err is not a
+// ValidateError, err is returned.
+func WithValue(err error, value, expected string) error {
+	validateErr, ok := err.(*ValidateError)
+	if !ok {
+		return err
+	}
+	e := *validateErr
+	e.Value = value
+	e.Expected = expected
+	e.err = err
+	return &e
+}
+
 func (e *ValidateError) Error() string {
 	return e.message
 }
 
+// Unwrap returns the wrapped error, e.g. ErrSerialNumFormat for ErrSerialNumTooShort.
+func (e *ValidateError) Unwrap() error {
+	return e.err
+}
+
+// Errors of a Validator. Use errors.Is to check for a specific error.
+var (
+	ErrOwnerCodeFormat         = newCodeError(CodeOwnerCodeFormat, PartOwnerCode, "owner code is not 3 letters long")
+	ErrOwnerNotRegistered      = newCodeError(CodeOwnerNotRegistered, PartOwnerCode, "owner code is not registered")
+	ErrOwnerBlocked            = newCodeError(CodeOwnerBlocked, PartOwnerCode, "owner code is blocked")
+	ErrEquipCatIDFormat        = newCodeError(CodeEquipCatFormat, PartEquipCatID, "equipment category id is not 1 letter long")
+	ErrEquipCatIDUnknown       = newCodeError(CodeEquipCatUnknown, PartEquipCatID, "equipment category id is not known")
+	ErrSerialNumFormat         = newCodeError(CodeSerialFormat, PartSerialNum, "serial number is not 6 numbers long")
+	ErrCheckDigitNotCalculable = newCodeError(CodeCheckDigitNotCalculable, PartCheckDigit, "check digit is not calculable")
+	ErrCheckDigitFormat        = newCodeError(CodeCheckDigitFormat, PartCheckDigit, "check digit is not a number")
+	ErrCheckDigitMismatch      = newCodeError(CodeCheckDigitMismatch, PartCheckDigit, "check digit is not the calculated check digit")
+	ErrLengthCodeFormat        = newCodeError(CodeLengthCodeFormat, PartLengthCode, "length code is not a valid number or a valid character")
+	ErrLengthCodeUnknown       = newCodeError(CodeLengthCodeUnknown, PartLengthCode, "length code is not valid")
+	ErrHeightWidthCodeFormat   = newCodeError(CodeHeightWidthCodeFormat, PartHeightWidthCode, "height and width code is not a valid number or a valid character")
+	ErrHeightWidthCodeUnknown  = newCodeError(CodeHeightWidthCodeUnknown, PartHeightWidthCode, "height and width code is not valid")
+	ErrTypeCodeFormat          = newCodeError(CodeTypeCodeFormat, PartTypeCode, "type code is not a valid number or a valid character")
+	ErrTypeCodeUnknown         = newCodeError(CodeTypeCodeUnknown, PartTypeCode, "type code is not valid")
+)
+
+// ErrSerialNumTooShort is an error of a Validator for a serial number with less than
+// 6 numbers. It wraps ErrSerialNumFormat, so errors.Is reports a match for both.
+var ErrSerialNumTooShort error = &ValidateError{
+	Code:    CodeSerialTooShort,
+	Part:    PartSerialNum,
+	message: "serial number is not 6 numbers long",
+	err:     ErrSerialNumFormat,
+}
+
+// Validator validates container numbers and size and type codes with decoders.
+// Use NewValidator to create one.
+type Validator struct {
+	ownerDecoder       OwnerDecoder
+	equipCatDecoder    EquipCatDecoder
+	lengthDecoder      LengthDecoder
+	heightWidthDecoder HeightWidthDecoder
+	typeDecoder        TypeDecoder
+}
+
+// NewValidator returns a new Validator that uses the decoders as data sources.
+func NewValidator(
+	ownerDecoder OwnerDecoder,
+	equipCatDecoder EquipCatDecoder,
+	lengthDecoder LengthDecoder,
+	heightWidthDecoder HeightWidthDecoder,
+	typeDecoder TypeDecoder,
+) *Validator {
+	return &Validator{
+		ownerDecoder:       ownerDecoder,
+		equipCatDecoder:    equipCatDecoder,
+		lengthDecoder:      lengthDecoder,
+		heightWidthDecoder: heightWidthDecoder,
+		typeDecoder:        typeDecoder,
+	}
+}
+
+// OwnerResult is the validation result of an owner code.
+type OwnerResult struct {
+	Code  string
+	Owner Owner
+	// BlockedReason is the reason of a blocked owner code.
+	BlockedReason string
+	Err           error
+}
+
+// EquipCatResult is the validation result of an equipment category ID.
+type EquipCatResult struct {
+	ID       string
+	EquipCat EquipCat
+	Err      error
+}
+
+// SerialNumResult is the validation result of a serial number.
+type SerialNumResult struct {
+	Value string
+	Err   error
+}
+
+// CheckDigitResult is the validation result of a check digit.
+type CheckDigitResult struct {
+	Value string
+	// CalcCheckDigit is the calculated check digit including 10.
+	// CalcCheckDigit is -1 if the check digit is not calculable.
+	CalcCheckDigit int
+	// ErrorProneNumbers are the container numbers with a valid check digit
+	// that result from transposing adjacent digits of a valid container number.
+	ErrorProneNumbers []TpNumber
+	Err               error
+}
+
+// LengthResult is the validation result of a length code.
+type LengthResult struct {
+	Code   string
+	Length Length
+	Err    error
+}
+
+// HeightWidthResult is the validation result of a height and width code.
+type HeightWidthResult struct {
+	Code   string
+	Height Height
+	Width  Width
+	Err    error
+}
+
+// TypeResult is the validation result of a type code.
+type TypeResult struct {
+	Code      string
+	TypeInfo  TypeInfo
+	GroupInfo GroupInfo
+	Err       error
+}
+
+// Result is the validation result of a container number with an optional size and type code.
+type Result struct {
+	Owner       OwnerResult
+	EquipCat    EquipCatResult
+	SerialNum   SerialNumResult
+	CheckDigit  CheckDigitResult
+	HasSizeType bool
+	Length      LengthResult
+	HeightWidth HeightWidthResult
+	Type        TypeResult
+}
+
+// Errors returns all errors of the validated parts in the order of the parts.
+func (r Result) Errors() []error {
+	errs := []error{r.Owner.Err, r.EquipCat.Err, r.SerialNum.Err, r.CheckDigit.Err}
+	if r.HasSizeType {
+		errs = append(errs, r.Length.Err, r.HeightWidth.Err, r.Type.Err)
+	}
+	var nonNilErrs []error
+	for _, err := range errs {
+		if err != nil {
+			nonNilErrs = append(nonNilErrs, err)
+		}
+	}
+	return nonNilErrs
+}
+
+// Valid returns true if all parts are valid.
+func (r Result) Valid() bool {
+	return len(r.Errors()) == 0
+}
+
+var (
+	ownerCodeRegexp   = regexp.MustCompile(`[A-Za-z]{3}`)
+	equipCatIDRegexp  = regexp.MustCompile(`[A-Za-z]`)
+	serialNumRegexp   = regexp.MustCompile(`\d{6}`)
+	checkDigitRegexp  = regexp.MustCompile(`\d`)
+	sizeCodeRegexp    = regexp.MustCompile(`[A-Za-z\d]`)
+	typeCodeRegexp    = regexp.MustCompile(`[A-Za-z\d]{2}`)
+	alphanumericRegex = regexp.MustCompile(`[A-Za-z\d]`)
+)
+
+// Validate validates a container number with an optional size and type code,
+// e.g. "ABC U 123456 0" or "ABCU1234560 22G1". Parts are matched in order
+// and separators between the parts are ignored. A part that cannot be
+// matched is validated as an empty value.
+func (v *Validator) Validate(in string) Result {
+	var r Result
+	var ownerCode, equipCatID, serialNum, checkDigit string
+	ownerCode, in = nextPart(in, ownerCodeRegexp)
+	equipCatID, in = nextPart(in, equipCatIDRegexp)
+	serialNum, in = nextPart(in, serialNumRegexp)
+	checkDigit, in = nextPart(in, checkDigitRegexp)
+
+	r.Owner = v.ValidateOwner(ownerCode)
+	r.EquipCat = v.ValidateEquipCat(equipCatID)
+	r.SerialNum = v.ValidateSerialNum(serialNum)
+	r.CheckDigit = v.ValidateCheckDigit(ownerCode, equipCatID, serialNum, checkDigit)
+
+	if !alphanumericRegex.MatchString(in) {
+		return r
+	}
+	var length, heightWidth, typeCode string
+	length, in = nextPart(in, sizeCodeRegexp)
+	heightWidth, in = nextPart(in, sizeCodeRegexp)
+	typeCode, _ = nextPart(in, typeCodeRegexp)
+
+	r.HasSizeType = true
+	r.Length = v.ValidateLength(length)
+	r.HeightWidth = v.ValidateHeightWidth(heightWidth)
+	r.Type = v.ValidateType(typeCode)
+	return r
+}
+
+// nextPart returns the upper case value of the first match of re in s and the rest of s after the match.
+func nextPart(s string, re *regexp.Regexp) (string, string) {
+	loc := re.FindStringIndex(s)
+	if loc == nil {
+		return "", s
+	}
+	return strings.ToUpper(s[loc[0]:loc[1]]), s[loc[1]:]
+}
+
+// ValidateOwner validates an owner code and decodes it to an owner.
+func (v *Validator) ValidateOwner(code string) OwnerResult {
+	r := OwnerResult{Code: code}
+	if IsOwnerCode(code) != nil {
+		r.Err = WithValue(ErrOwnerCodeFormat, code, "3 letters")
+		return r
+	}
+	if blocker, ok := v.ownerDecoder.(OwnerBlocker); ok {
+		if blocked, reason := blocker.Blocked(code); blocked {
+			r.BlockedReason = reason
+			r.Err = WithValue(ErrOwnerBlocked, code, "")
+			return r
+		}
+	}
+	found, owner := v.ownerDecoder.Decode(code)
+	if !found {
+		r.Err = WithValue(ErrOwnerNotRegistered, code, "registered owner code")
+		return r
+	}
+	r.Owner = owner
+	return r
+}
+
+// ValidateEquipCat validates an equipment category ID and decodes it to an equipment category.
+func (v *Validator) ValidateEquipCat(id string) EquipCatResult {
+	r := EquipCatResult{ID: id}
+	if IsEquipCatID(id) != nil {
+		r.Err = WithValue(ErrEquipCatIDFormat, id, "1 letter")
+		return r
+	}
+	found, equipCat := v.equipCatDecoder.Decode(id)
+	if !found {
+		r.Err = WithValue(ErrEquipCatIDUnknown, id, "known equipment category id")
+		return r
+	}
+	r.EquipCat = equipCat
+	return r
+}
+
+// ValidateSerialNum validates that a serial number consists of 6 numbers. An empty
+// serial number or a serial number with other characters than numbers has the
+// code CodeSerialFormat and a serial number of 1 to 5 numbers has the code
+// CodeSerialTooShort.
+func (v *Validator) ValidateSerialNum(serialNum string) SerialNumResult {
+	return SerialNumResult{Value: serialNum, Err: serialNumErr(serialNum)}
+}
+
+func serialNumErr(serialNum string) error {
+	switch {
+	case len(serialNum) > 0 && len(serialNum) < 6 && isDigits(serialNum):
+		return WithValue(ErrSerialNumTooShort, serialNum, "6 numbers")
+	case !isSerialNum(serialNum):
+		return WithValue(ErrSerialNumFormat, serialNum, "6 numbers")
+	}
+	return nil
+}
+
+// ValidateCheckDigit calculates the check digit for owner code, equipment category ID
+// and serial number and compares it with checkDigit. For a valid check digit the
+// error-prone container numbers are determined.
+func (v *Validator) ValidateCheckDigit(ownerCode, equipCatID, serialNum, checkDigit string) CheckDigitResult {
+	r := CheckDigitResult{Value: checkDigit, CalcCheckDigit: -1}
+	if IsOwnerCode(ownerCode) != nil || IsEquipCatID(equipCatID) != nil || !isSerialNum(serialNum) {
+		r.Err = WithValue(ErrCheckDigitNotCalculable, checkDigit, "")
+		return r
+	}
+
+	equipCatIDRune, _ := utf8.DecodeRuneInString(equipCatID)
+	serialNumInt, _ := strconv.Atoi(serialNum)
+	r.CalcCheckDigit = CalcCheckDigit(ownerCode, equipCatIDRune, serialNumInt)
+
+	if len(checkDigit) != 1 || checkDigit[0] < '0' || checkDigit[0] > '9' {
+		r.Err = WithValue(ErrCheckDigitFormat, checkDigit, strconv.Itoa(r.CalcCheckDigit%10))
+		return r
+	}
+	if int(checkDigit[0]-'0') != r.CalcCheckDigit%10 {
+		r.Err = WithValue(ErrCheckDigitMismatch, checkDigit, strconv.Itoa(r.CalcCheckDigit%10))
+		return r
+	}
+	r.ErrorProneNumbers = CheckTransposition(ownerCode, equipCatIDRune, serialNumInt, r.CalcCheckDigit)
+	return r
+}
+
+// ValidateLength validates a length code and decodes it to a length.
+func (v *Validator) ValidateLength(code string) LengthResult {
+	r := LengthResult{Code: code}
+	if IsLengthCode(code) != nil {
+		r.Err = WithValue(ErrLengthCodeFormat, code, "1 number or letter")
+		return r
+	}
+	found, length := v.lengthDecoder.Decode(code)
+	if !found {
+		r.Err = WithValue(ErrLengthCodeUnknown, code, "known length code")
+		return r
+	}
+	r.Length = length
+	return r
+}
+
+// ValidateHeightWidth validates a height and width code and decodes it to height and width.
+func (v *Validator) ValidateHeightWidth(code string) HeightWidthResult {
+	r := HeightWidthResult{Code: code}
+	if IsHeightWidthCode(code) != nil {
+		r.Err = WithValue(ErrHeightWidthCodeFormat, code, "1 number or letter")
+		return r
+	}
+	found, height, width := v.heightWidthDecoder.Decode(code)
+	if !found {
+		r.Err = WithValue(ErrHeightWidthCodeUnknown, code, "known height and width code")
+		return r
+	}
+	r.Height = height
+	r.Width = width
+	return r
+}
+
+// ValidateType validates a type code and decodes it to type and group information.
+func (v *Validator) ValidateType(code string) TypeResult {
+	r := TypeResult{Code: code}
+	if IsTypeCode(code) != nil {
+		r.Err = WithValue(ErrTypeCodeFormat, code, "2 numbers or letters")
+		return r
+	}
+	found, typeInfo, groupInfo := v.typeDecoder.Decode(code)
+	if !found {
+		r.Err = WithValue(ErrTypeCodeUnknown, code, "known type code")
+		return r
+	}
+	r.TypeInfo = typeInfo
+	r.GroupInfo = groupInfo
+	return r
+}
+
+func isSerialNum(s string) bool {
+	return len(s) == 6 && isDigits(s)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 func isOneUpperAlphanumericChar(code string) error {
 	if len(code) != 1 {
 		return NewValidateError(fmt.Sprintf("%s is not 1 digit", code))
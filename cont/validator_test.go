@@ -0,0 +1,285 @@
+package cont
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+type dummyOwnerDecoder struct{}
+
+func (dummyOwnerDecoder) Decode(code string) (bool, Owner) {
+	if code != "ABC" {
+		return false, Owner{}
+	}
+	return true, Owner{Code: "ABC", Company: "some-company", City: "some-city", Country: "some-country"}
+}
+
+type dummyEquipCatDecoder struct{}
+
+func (dummyEquipCatDecoder) Decode(ID string) (bool, EquipCat) {
+	if ID != "U" {
+		return false, EquipCat{}
+	}
+	return true, EquipCat{Value: ID, Info: "some-equip-cat"}
+}
+
+type dummyLengthDecoder struct{}
+
+func (dummyLengthDecoder) Decode(code string) (bool, Length) {
+	return code == "2", "some-length"
+}
+
+type dummyHeightWidthDecoder struct{}
+
+func (dummyHeightWidthDecoder) Decode(code string) (bool, Height, Width) {
+	return code == "2", "some-height", "some-width"
+}
+
+type dummyTypeDecoder struct{}
+
+func (dummyTypeDecoder) Decode(code string) (bool, TypeInfo, GroupInfo) {
+	return code == "G1", "some-type", "some-group"
+}
+
+func newDummyValidator() *Validator {
+	return NewValidator(
+		dummyOwnerDecoder{},
+		dummyEquipCatDecoder{},
+		dummyLengthDecoder{},
+		dummyHeightWidthDecoder{},
+		dummyTypeDecoder{},
+	)
+}
+
+func TestValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name               string
+		in                 string
+		wantErrs           []error
+		wantCalcCheckDigit int
+		wantErrorProne     []TpNumber
+		wantHasSizeType    bool
+	}{
+		{
+			"Validate valid container number",
+			"abc u 123456 0",
+			nil,
+			0,
+			nil,
+			false,
+		},
+		{
+			"Validate valid container number with error-prone serial numbers",
+			"ABCU6813040",
+			nil,
+			0,
+			[]TpNumber{
+				{Number{"ABC", 'U', 681034, 0}, 3},
+				{Number{"ABC", 'U', 681340, 0}, 4},
+			},
+			false,
+		},
+		{
+			"Validate container number with wrong check digit",
+			"ABCU1231231",
+			[]error{ErrCheckDigitMismatch},
+			7,
+			nil,
+			false,
+		},
+		{
+			"Validate container number with unknown owner and equipment category ID",
+			"XYZ J 123456 3",
+			[]error{ErrOwnerNotRegistered, ErrEquipCatIDUnknown},
+			3,
+			nil,
+			false,
+		},
+		{
+			"Validate owner code only",
+			"ABC",
+			[]error{ErrEquipCatIDFormat, ErrSerialNumFormat, ErrCheckDigitNotCalculable},
+			-1,
+			nil,
+			false,
+		},
+		{
+			"Validate container number with size and type",
+			"ABCU1234560 22G1",
+			nil,
+			0,
+			nil,
+			true,
+		},
+		{
+			"Validate container number with invalid size and type",
+			"ABCU1234560 4510",
+			[]error{ErrLengthCodeUnknown, ErrHeightWidthCodeUnknown, ErrTypeCodeUnknown},
+			0,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newDummyValidator().Validate(tt.in)
+			gotErrs := got.Errors()
+			if len(gotErrs) != len(tt.wantErrs) {
+				t.Fatalf("Validate() errors = %v, want %v", gotErrs, tt.wantErrs)
+			}
+			for i, err := range gotErrs {
+				if !errors.Is(err, tt.wantErrs[i]) {
+					t.Errorf("Validate() errors = %v, want %v", gotErrs, tt.wantErrs)
+				}
+			}
+			if got.Valid() != (tt.wantErrs == nil) {
+				t.Errorf("Validate() valid = %v, want %v", got.Valid(), tt.wantErrs == nil)
+			}
+			if got.CheckDigit.CalcCheckDigit != tt.wantCalcCheckDigit {
+				t.Errorf("Validate() calculated check digit = %v, want %v", got.CheckDigit.CalcCheckDigit, tt.wantCalcCheckDigit)
+			}
+			if !slices.Equal(got.CheckDigit.ErrorProneNumbers, tt.wantErrorProne) {
+				t.Errorf("Validate() error-prone numbers = %v, want %v", got.CheckDigit.ErrorProneNumbers, tt.wantErrorProne)
+			}
+			if got.HasSizeType != tt.wantHasSizeType {
+				t.Errorf("Validate() has size type = %v, want %v", got.HasSizeType, tt.wantHasSizeType)
+			}
+		})
+	}
+}
+
+type blockingOwnerDecoder struct {
+	dummyOwnerDecoder
+}
+
+func (blockingOwnerDecoder) Blocked(code string) (bool, string) {
+	return code == "ABC", "retired"
+}
+
+func TestValidator_ValidateOwnerBlocked(t *testing.T) {
+	validator := NewValidator(
+		blockingOwnerDecoder{},
+		dummyEquipCatDecoder{},
+		dummyLengthDecoder{},
+		dummyHeightWidthDecoder{},
+		dummyTypeDecoder{},
+	)
+	got := validator.ValidateOwner("ABC")
+	if !errors.Is(got.Err, ErrOwnerBlocked) {
+		t.Errorf("ValidateOwner() error = %v, want %v", got.Err, ErrOwnerBlocked)
+	}
+	if got.BlockedReason != "retired" {
+		t.Errorf("ValidateOwner() BlockedReason = %v, want %v", got.BlockedReason, "retired")
+	}
+	if got := newDummyValidator().ValidateOwner("ABC"); got.Err != nil {
+		t.Errorf("ValidateOwner() error = %v, want no error without OwnerBlocker", got.Err)
+	}
+}
+
+func TestValidator_ValidateSerialNum(t *testing.T) {
+	tests := []struct {
+		name      string
+		serialNum string
+		wantCode  ErrorCode
+	}{
+		{"Validate serial number", "123456", ""},
+		{"Return format error for empty serial number", "", CodeSerialFormat},
+		{"Return format error for serial number with letter", "12A456", CodeSerialFormat},
+		{"Return format error for short serial number with letter", "12A45", CodeSerialFormat},
+		{"Return too short error for 5 numbers", "12345", CodeSerialTooShort},
+		{"Return format error for 7 numbers", "1234567", CodeSerialFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newDummyValidator().ValidateSerialNum(tt.serialNum).Err
+			var gotCode ErrorCode
+			var validateErr *ValidateError
+			if errors.As(err, &validateErr) {
+				gotCode = validateErr.Code
+			}
+			if gotCode != tt.wantCode {
+				t.Errorf("ValidateSerialNum() code = %v, want %v", gotCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateErrorCodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        string
+		wantCodes []ErrorCode
+		wantParts []string
+		wantVals  []string
+		wantExps  []string
+	}{
+		{
+			"Return codes of unknown owner and wrong check digit",
+			"XYZU1231231",
+			[]ErrorCode{CodeOwnerNotRegistered, CodeCheckDigitMismatch},
+			[]string{PartOwnerCode, PartCheckDigit},
+			[]string{"XYZ", "1"},
+			[]string{"registered owner code", "7"},
+		},
+		{
+			"Return codes of missing parts",
+			"ABC U",
+			[]ErrorCode{CodeSerialFormat, CodeCheckDigitNotCalculable},
+			[]string{PartSerialNum, PartCheckDigit},
+			[]string{"", ""},
+			[]string{"6 numbers", ""},
+		},
+		{
+			"Return codes of unknown equipment category ID and size and type",
+			"ABCJ1234560 4510",
+			[]ErrorCode{CodeEquipCatUnknown, CodeCheckDigitMismatch, CodeLengthCodeUnknown, CodeHeightWidthCodeUnknown, CodeTypeCodeUnknown},
+			[]string{PartEquipCatID, PartCheckDigit, PartLengthCode, PartHeightWidthCode, PartTypeCode},
+			[]string{"J", "0", "4", "5", "10"},
+			[]string{"known equipment category id", "3", "known length code", "known height and width code", "known type code"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotErrs := newDummyValidator().Validate(tt.in).Errors()
+			if len(gotErrs) != len(tt.wantCodes) {
+				t.Fatalf("Validate() errors = %v, want codes %v", gotErrs, tt.wantCodes)
+			}
+			for i, err := range gotErrs {
+				var validateErr *ValidateError
+				if !errors.As(err, &validateErr) {
+					t.Fatalf("Validate() error = %v, want *ValidateError", err)
+				}
+				if validateErr.Code != tt.wantCodes[i] {
+					t.Errorf("Validate() code = %v, want %v", validateErr.Code, tt.wantCodes[i])
+				}
+				if validateErr.Part != tt.wantParts[i] {
+					t.Errorf("Validate() part = %v, want %v", validateErr.Part, tt.wantParts[i])
+				}
+				if validateErr.Value != tt.wantVals[i] {
+					t.Errorf("Validate() value = %v, want %v", validateErr.Value, tt.wantVals[i])
+				}
+				if validateErr.Expected != tt.wantExps[i] {
+					t.Errorf("Validate() expected = %v, want %v", validateErr.Expected, tt.wantExps[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWithValue(t *testing.T) {
+	err := WithValue(ErrSerialNumTooShort, "123", "6 numbers")
+	if !errors.Is(err, ErrSerialNumTooShort) || !errors.Is(err, ErrSerialNumFormat) {
+		t.Errorf("WithValue() error = %v, want %v and %v", err, ErrSerialNumTooShort, ErrSerialNumFormat)
+	}
+	if errors.Is(err, ErrCheckDigitFormat) {
+		t.Errorf("WithValue() error = %v, want not %v", err, ErrCheckDigitFormat)
+	}
+	if err.Error() != ErrSerialNumTooShort.Error() {
+		t.Errorf("WithValue() message = %v, want %v", err.Error(), ErrSerialNumTooShort.Error())
+	}
+	other := errors.New("other")
+	if got := WithValue(other, "123", ""); got != other {
+		t.Errorf("WithValue() = %v, want %v", got, other)
+	}
+}
@@ -0,0 +1,102 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	// Needed for package embed.
+	_ "embed"
+
+	"github.com/meyermarcel/icm/cont"
+)
+
+const legacySizeTypeFileName = "legacy-size-type.json"
+
+//go:embed legacy-size-type.json
+var legacySizeTypeJSON []byte
+
+type legacySizeType struct {
+	Length      map[string]string `json:"length"`
+	HeightWidth map[string]string `json:"heightWidth"`
+	Type        map[string]string `json:"type"`
+}
+
+// NewLegacySizeTypeDecoder writes legacy size and type file to path if it not exists and
+// returns a struct that uses this file as a data source.
+func NewLegacySizeTypeDecoder(path string) (*LegacySizeTypeDecoder, error) {
+	pathToLegacySizeType := filepath.Join(path, legacySizeTypeFileName)
+	if err := initFile(pathToLegacySizeType, legacySizeTypeJSON); err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(pathToLegacySizeType)
+	if err != nil {
+		return nil, err
+	}
+
+	var l legacySizeType
+	if err := json.Unmarshal(b, &l); err != nil {
+		return nil, err
+	}
+	for legacyCode, code := range l.Length {
+		if err := isLegacyCode(legacyCode, 1); err != nil {
+			return nil, err
+		}
+		if err := cont.IsLengthCode(code); err != nil {
+			return nil, err
+		}
+	}
+	for legacyCode, code := range l.HeightWidth {
+		if err := isLegacyCode(legacyCode, 1); err != nil {
+			return nil, err
+		}
+		if err := cont.IsHeightWidthCode(code); err != nil {
+			return nil, err
+		}
+	}
+	for legacyCode, code := range l.Type {
+		if err := isLegacyCode(legacyCode, 2); err != nil {
+			return nil, err
+		}
+		if err := cont.IsTypeCode(code); err != nil {
+			return nil, err
+		}
+	}
+	return &LegacySizeTypeDecoder{l}, nil
+}
+
+// isLegacyCode returns nil if code has count digits.
+func isLegacyCode(code string, count int) error {
+	if len(code) != count {
+		return fmt.Errorf("legacy code %s is not %d digits long", code, count)
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("legacy code %s is not %d digits long", code, count)
+		}
+	}
+	return nil
+}
+
+type LegacySizeTypeDecoder struct {
+	legacySizeType
+}
+
+// DecodeLength returns the current length code for a legacy length code.
+func (lstd *LegacySizeTypeDecoder) DecodeLength(code string) (bool, string) {
+	val, ok := lstd.Length[code]
+	return ok, val
+}
+
+// DecodeHeightWidth returns the current height and width code for a legacy height and width code.
+func (lstd *LegacySizeTypeDecoder) DecodeHeightWidth(code string) (bool, string) {
+	val, ok := lstd.HeightWidth[code]
+	return ok, val
+}
+
+// DecodeType returns the current type code for a legacy type code.
+func (lstd *LegacySizeTypeDecoder) DecodeType(code string) (bool, string) {
+	val, ok := lstd.Type[code]
+	return ok, val
+}
@@ -0,0 +1,40 @@
+package file
+
+import (
+	"testing"
+)
+
+func TestLegacySizeTypeDecoder(t *testing.T) {
+	decoder, err := NewLegacySizeTypeDecoder(t.TempDir())
+	if err != nil {
+		t.Errorf("NewLegacySizeTypeDecoder() error = %v, want no err", err)
+		return
+	}
+
+	tests := []struct {
+		name       string
+		legacyCode string
+		want       string
+		wantFound  bool
+	}{
+		{"Decode general purpose container", "2210", "22G1", true},
+		{"Decode general purpose container with 8'6\" height", "4310", "42G1", true},
+		{"Decode high cube reefer", "4532", "45R1", true},
+		{"Decode open top container", "2251", "22U1", true},
+		{"Do not decode unknown type code", "2299", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			foundLength, length := decoder.DecodeLength(tt.legacyCode[0:1])
+			foundHeightWidth, heightWidth := decoder.DecodeHeightWidth(tt.legacyCode[1:2])
+			foundType, typeCode := decoder.DecodeType(tt.legacyCode[2:4])
+			found := foundLength && foundHeightWidth && foundType
+			if found != tt.wantFound {
+				t.Errorf("Decode() found = %v, want %v", found, tt.wantFound)
+			}
+			if got := length + heightWidth + typeCode; found && got != tt.want {
+				t.Errorf("Decode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,14 +1,21 @@
 package file
 
 import (
+	"bytes"
 	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
 	// Needed for package embed.
 	_ "embed"
 
+	"github.com/meyermarcel/icm/configs"
 	"github.com/meyermarcel/icm/cont"
 )
 
@@ -24,45 +31,94 @@ type owner struct {
 	Company string
 	City    string
 	Country string
+	// Source is the name of the source of the owner.
+	Source string
 }
 
 type OwnerDecoder struct {
-	owners map[string]owner
+	owners  map[string]owner
+	blocked map[string]string
 }
 
 // NewOwnerDecoder writes owner file to path if it not exists and
 // returns a struct that uses this file as a data source.
 func NewOwnerDecoder(remoteOwnersPath, customOwnersPath string) (*OwnerDecoder, error) {
-	decoder := &OwnerDecoder{}
-
-	if err := initFile(remoteOwnersPath, ownerCSV); err != nil {
+	if err := InitOwnerFile(remoteOwnersPath); err != nil {
 		return nil, err
 	}
+	return NewLayeredOwnerDecoder([]configs.OwnerSource{
+		{Type: configs.OwnerSourceFile, Path: remoteOwnersPath},
+		{Type: configs.OwnerSourceFile, Path: customOwnersPath, Optional: true},
+	}, nil)
+}
 
-	ownersMap, err := readFile(remoteOwnersPath)
-	if err != nil {
-		return nil, err
+// InitOwnerFile writes owner file to path if it not exists.
+func InitOwnerFile(path string) error {
+	return initFile(path, ownerCSV)
+}
+
+// NewLayeredOwnerDecoder returns a struct that uses the sources as data sources.
+// An owner of a source overrides an owner with the same code of all sources
+// before. Paths of sources must be absolute. Blocked owner codes map to their reason.
+func NewLayeredOwnerDecoder(sources []configs.OwnerSource, blocked map[string]string) (*OwnerDecoder, error) {
+	ownersMap := make(map[string]owner)
+	for _, source := range sources {
+		if source.Type != configs.OwnerSourceEmbedded && source.Optional {
+			if _, err := os.Stat(source.Path); errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+		}
+		layers, err := readSource(source)
+		if err != nil {
+			return nil, err
+		}
+		for _, layer := range layers {
+			maps.Copy(ownersMap, layer)
+		}
 	}
 
-	if len(ownersMap) == 0 {
-		return nil, fmt.Errorf("%v: no owners found", remoteOwnersPath)
+	decoder := &OwnerDecoder{owners: ownersMap, blocked: blocked}
+	if len(decoder.GetAllOwnerCodes()) == 0 {
+		return nil, fmt.Errorf("no owners found in %d owner sources that are not blocked", len(sources))
 	}
 
-	if _, err := os.Stat(customOwnersPath); err == nil {
+	return decoder, nil
+}
 
-		customOwnersMap, err := readFile(customOwnersPath)
+// readSource returns the owners of source in order of precedence.
+func readSource(source configs.OwnerSource) ([]map[string]owner, error) {
+	switch source.Type {
+	case configs.OwnerSourceEmbedded:
+		ownersMap, err := readCSV(bytes.NewReader(ownerCSV), "embedded")
+		if err != nil {
+			return nil, fmt.Errorf("embedded: %w", err)
+		}
+		return []map[string]owner{ownersMap}, nil
+	case configs.OwnerSourceFile:
+		ownersMap, err := readFile(source.Path)
 		if err != nil {
 			return nil, err
 		}
-
-		for k, v := range customOwnersMap {
-			ownersMap[k] = v
+		return []map[string]owner{ownersMap}, nil
+	case configs.OwnerSourceDir:
+		entries, err := os.ReadDir(source.Path)
+		if err != nil {
+			return nil, err
 		}
+		var layers []map[string]owner
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".csv" {
+				continue
+			}
+			ownersMap, err := readFile(filepath.Join(source.Path, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			layers = append(layers, ownersMap)
+		}
+		return layers, nil
 	}
-
-	decoder.owners = ownersMap
-
-	return decoder, nil
+	return nil, fmt.Errorf("%s is not a type of owner source", source.Type)
 }
 
 func readFile(path string) (map[string]owner, error) {
@@ -71,14 +127,16 @@ func readFile(path string) (map[string]owner, error) {
 		return nil, err
 	}
 
-	ownersMap, err := readCSV(f)
+	defer f.Close()
+
+	ownersMap, err := readCSV(f, filepath.Base(path))
 	if err != nil {
 		return nil, fmt.Errorf("%v: %w", path, err)
 	}
 	return ownersMap, nil
 }
 
-func readCSV(r io.Reader) (map[string]owner, error) {
+func readCSV(r io.Reader, source string) (map[string]owner, error) {
 	csvReader := csv.NewReader(r)
 
 	csvReader.Comma = csvSep
@@ -106,14 +164,27 @@ func readCSV(r io.Reader) (map[string]owner, error) {
 			Company: rec[1],
 			City:    rec[2],
 			Country: rec[3],
+			Source:  source,
 		}
 	}
 
 	return ownersMap, nil
 }
 
-// Decode returns an owner for an owner code.
+// ReadOwnersCSV reads CSV from in and returns the owners sorted by owner code.
+func ReadOwnersCSV(in io.Reader) ([]cont.Owner, error) {
+	ownersMap, err := readCSV(in, "")
+	if err != nil {
+		return nil, err
+	}
+	return (&OwnerDecoder{owners: ownersMap}).GetAllOwners(), nil
+}
+
+// Decode returns an owner for an owner code that is not blocked.
 func (od *OwnerDecoder) Decode(code string) (bool, cont.Owner) {
+	if _, blocked := od.blocked[code]; blocked {
+		return false, cont.Owner{}
+	}
 	if val, ok := od.owners[code]; ok {
 		return true, cont.Owner{
 			Code:    code,
@@ -125,15 +196,50 @@ func (od *OwnerDecoder) Decode(code string) (bool, cont.Owner) {
 	return false, cont.Owner{}
 }
 
-// GetAllOwnerCodes returns a count of owner codes.
+// Source returns the name of the source of an owner code. The name is the file name
+// of a file, embedded for the embedded owners or empty for an unknown owner code.
+func (od *OwnerDecoder) Source(code string) string {
+	return od.owners[code].Source
+}
+
+// Blocked returns true and the reason if an owner code is blocked.
+func (od *OwnerDecoder) Blocked(code string) (bool, string) {
+	reason, ok := od.blocked[code]
+	return ok, reason
+}
+
+// GetAllOwnerCodes returns all owner codes that are not blocked.
 func (od *OwnerDecoder) GetAllOwnerCodes() []string {
 	var codes []string
 	for ownerCode := range od.owners {
+		if _, blocked := od.blocked[ownerCode]; blocked {
+			continue
+		}
 		codes = append(codes, ownerCode)
 	}
 	return codes
 }
 
+// GetAllOwners returns all owners that are not blocked sorted by owner code.
+func (od *OwnerDecoder) GetAllOwners() []cont.Owner {
+	owners := make([]cont.Owner, 0, len(od.owners))
+	for ownerCode, o := range od.owners {
+		if _, blocked := od.blocked[ownerCode]; blocked {
+			continue
+		}
+		owners = append(owners, cont.Owner{
+			Code:    ownerCode,
+			Company: o.Company,
+			City:    o.City,
+			Country: o.Country,
+		})
+	}
+	slices.SortFunc(owners, func(a, b cont.Owner) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+	return owners
+}
+
 func initFile(path string, content []byte) error {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		if err := os.WriteFile(path, content, 0o644); err != nil {
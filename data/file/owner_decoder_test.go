@@ -5,14 +5,18 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"testing"
+
+	"github.com/meyermarcel/icm/configs"
+	"github.com/meyermarcel/icm/cont"
 )
 
 func TestNewOwnerDecoder(t *testing.T) {
 	want := &OwnerDecoder{
 		owners: map[string]owner{
-			"AAA": {Company: "my company", City: "my city", Country: "my country"},
-			"CUS": {Company: "my custom company", City: "my custom city", Country: "my custom country"},
+			"AAA": {Company: "my company", City: "my city", Country: "my country", Source: "remote-owners.csv"},
+			"CUS": {Company: "my custom company", City: "my custom city", Country: "my custom country", Source: "custom-owners.csv"},
 		},
 	}
 
@@ -40,3 +44,84 @@ func TestNewOwnerDecoder(t *testing.T) {
 		t.Errorf("NewOwnerDecoder() got = %v, want %v", got, want)
 	}
 }
+
+func TestOwnerDecoder_GetAllOwners(t *testing.T) {
+	decoder := &OwnerDecoder{
+		owners: map[string]owner{
+			"CUS": {Company: "my custom company", City: "my custom city", Country: "my custom country"},
+			"AAA": {Company: "my company", City: "my city", Country: "my country"},
+		},
+	}
+	want := []cont.Owner{
+		{Code: "AAA", Company: "my company", City: "my city", Country: "my country"},
+		{Code: "CUS", Company: "my custom company", City: "my custom city", Country: "my custom country"},
+	}
+	if got := decoder.GetAllOwners(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAllOwners() got = %v, want %v", got, want)
+	}
+}
+
+func TestNewLayeredOwnerDecoder(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "file.csv")
+	_ = os.WriteFile(filePath, []byte("AAA;file company;file city;file country\nBBB;b;b;b\nCCC;c;c;c"), 0o644)
+
+	dirPath := filepath.Join(dir, "dir")
+	_ = os.Mkdir(dirPath, 0o700)
+	_ = os.WriteFile(filepath.Join(dirPath, "1.csv"), []byte("BBB;1 company;1 city;1 country\nCCC;1;1;1"), 0o644)
+	_ = os.WriteFile(filepath.Join(dirPath, "2.csv"), []byte("CCC;2 company;2 city;2 country"), 0o644)
+	_ = os.WriteFile(filepath.Join(dirPath, "ignored.txt"), []byte("no CSV"), 0o644)
+
+	got, err := NewLayeredOwnerDecoder([]configs.OwnerSource{
+		{Type: configs.OwnerSourceEmbedded},
+		{Type: configs.OwnerSourceFile, Path: filePath},
+		{Type: configs.OwnerSourceDir, Path: dirPath},
+		{Type: configs.OwnerSourceFile, Path: filepath.Join(dir, "missing.csv"), Optional: true},
+	}, map[string]string{"BBB": "retired"})
+	if err != nil {
+		t.Fatalf("NewLayeredOwnerDecoder() error = %v, want no err", err)
+	}
+
+	want := map[string]owner{
+		"AAA": {Company: "file company", City: "file city", Country: "file country", Source: "file.csv"},
+		"BBB": {Company: "1 company", City: "1 city", Country: "1 country", Source: "1.csv"},
+		"CCC": {Company: "2 company", City: "2 city", Country: "2 country", Source: "2.csv"},
+	}
+	if !reflect.DeepEqual(got.owners, want) {
+		t.Errorf("NewLayeredOwnerDecoder() owners = %v, want %v", got.owners, want)
+	}
+	if source := got.Source("CCC"); source != "2.csv" {
+		t.Errorf("Source() = %v, want %v", source, "2.csv")
+	}
+	if blocked, reason := got.Blocked("BBB"); !blocked || reason != "retired" {
+		t.Errorf("Blocked() = %v, %v, want %v, %v", blocked, reason, true, "retired")
+	}
+	if codes := got.GetAllOwnerCodes(); slices.Contains(codes, "BBB") || len(codes) != 2 {
+		t.Errorf("GetAllOwnerCodes() = %v, want codes without blocked BBB", codes)
+	}
+	if found, _ := got.Decode("BBB"); found {
+		t.Errorf("Decode() found = %v, want %v for blocked BBB", found, false)
+	}
+}
+
+func TestNewLayeredOwnerDecoderErrors(t *testing.T) {
+	dir := t.TempDir()
+	emptyPath := filepath.Join(dir, "empty.csv")
+	_ = os.WriteFile(emptyPath, nil, 0o644)
+
+	tests := []struct {
+		name    string
+		sources []configs.OwnerSource
+	}{
+		{"Missing file", []configs.OwnerSource{{Type: configs.OwnerSourceFile, Path: filepath.Join(dir, "missing.csv")}}},
+		{"No owners", []configs.OwnerSource{{Type: configs.OwnerSourceFile, Path: emptyPath}}},
+		{"All owners blocked", []configs.OwnerSource{{Type: configs.OwnerSourceEmbedded}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewLayeredOwnerDecoder(tt.sources, map[string]string{"AAA": "retired"}); err == nil {
+				t.Errorf("NewLayeredOwnerDecoder() error = nil, want error")
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package file
 
 import (
 	"encoding/json"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 
 	// Needed for package embed.
 	_ "embed"
@@ -67,6 +69,11 @@ func (ld *LengthDecoder) Decode(code string) (bool, cont.Length) {
 	return false, ""
 }
 
+// AllLengthCodes returns all length codes sorted.
+func (ld *LengthDecoder) AllLengthCodes() []string {
+	return slices.Sorted(maps.Keys(ld.lengths))
+}
+
 type HeightWidthDecoder struct {
 	heightWidths map[string]heightWidth
 }
@@ -78,3 +85,8 @@ func (hwd *HeightWidthDecoder) Decode(code string) (bool, cont.Height, cont.Widt
 	}
 	return false, "", ""
 }
+
+// AllHeightWidthCodes returns all height and width codes sorted.
+func (hwd *HeightWidthDecoder) AllHeightWidthCodes() []string {
+	return slices.Sorted(maps.Keys(hwd.heightWidths))
+}
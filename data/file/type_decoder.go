@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 
 	// Needed for package embed.
 	_ "embed"
@@ -80,3 +81,15 @@ func (tgd *TypeAndGroupDecoder) Decode(code string) (bool, cont.TypeInfo, cont.G
 
 	return true, typeInfo, groupInfo
 }
+
+// AllTypeCodes returns all type codes with a group sorted.
+func (tgd *TypeAndGroupDecoder) AllTypeCodes() []string {
+	var codes []string
+	for code := range tgd.types {
+		if _, ok := tgd.groups[code[0:1]]; ok {
+			codes = append(codes, code)
+		}
+	}
+	slices.Sort(codes)
+	return codes
+}
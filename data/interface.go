@@ -11,10 +11,17 @@ type OwnerDecoder interface {
 	Decode(code string) (bool, cont.Owner)
 
 	GetAllOwnerCodes() []string
+
+	GetAllOwners() []cont.Owner
+
+	// Source returns the name of the source of an owner code.
+	Source(code string) string
 }
 
 type WriteOwnersCSVFunc func(newOwners []cont.Owner, out io.Writer) error
 
+type ReadOwnersCSVFunc func(in io.Reader) ([]cont.Owner, error)
+
 // EquipCatDecoder decodes an ID to an equipment category.
 type EquipCatDecoder interface {
 	Decode(ID string) (bool, cont.EquipCat)
@@ -25,16 +32,32 @@ type EquipCatDecoder interface {
 // LengthDecoder decodes a code to a length.
 type LengthDecoder interface {
 	Decode(code string) (bool, cont.Length)
+
+	AllLengthCodes() []string
 }
 
 // HeightWidthDecoder decodes a code to height and width.
 type HeightWidthDecoder interface {
 	Decode(code string) (bool, cont.Height, cont.Width)
+
+	AllHeightWidthCodes() []string
 }
 
 // TypeDecoder decodes a code to type and group information.
 type TypeDecoder interface {
 	Decode(code string) (bool, cont.TypeInfo, cont.GroupInfo)
+
+	AllTypeCodes() []string
+}
+
+// LegacySizeTypeDecoder decodes codes of the size and type code table of 1984
+// to current codes.
+type LegacySizeTypeDecoder interface {
+	DecodeLength(code string) (bool, string)
+
+	DecodeHeightWidth(code string) (bool, string)
+
+	DecodeType(code string) (bool, string)
 }
 
 // TimestampUpdater updates a timestamp with an implemented time.
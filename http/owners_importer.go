@@ -0,0 +1,204 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/meyermarcel/icm/cont"
+	"github.com/meyermarcel/icm/data"
+)
+
+// Formats of files with owners.
+const (
+	FormatAuto = "auto"
+	FormatHTML = "html"
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+type ownersImporter struct {
+	reader io.Reader
+	parse  func(io.Reader) ([]cont.Owner, error)
+}
+
+// NewOwnersImporter returns an OwnersDownloader that imports owners from reader
+// instead of downloading them. The format is html like the downloaded page, csv
+// or json. With format auto the format is detected by the content of reader.
+func NewOwnersImporter(reader io.Reader, format string) (OwnersDownloader, error) {
+	if format == FormatAuto {
+		bufReader := bufio.NewReader(reader)
+		reader = bufReader
+		peek, _ := bufReader.Peek(512)
+		format = detectFormat(peek)
+	}
+
+	switch format {
+	case FormatHTML:
+		return &ownersImporter{reader: reader, parse: parseOwners}, nil
+	case FormatCSV:
+		return &ownersImporter{reader: reader, parse: parseOwnersCSV}, nil
+	case FormatJSON:
+		return &ownersImporter{reader: reader, parse: parseOwnersJSON}, nil
+	}
+	return nil, fmt.Errorf("%s is not %s, %s, %s or %s", format, FormatAuto, FormatHTML, FormatCSV, FormatJSON)
+}
+
+func (oi *ownersImporter) Download() ([]cont.Owner, error) {
+	return oi.parse(oi.reader)
+}
+
+// detectFormat returns the format of content by its first character.
+func detectFormat(content []byte) string {
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+	content = bytes.TrimSpace(content)
+	switch {
+	case bytes.HasPrefix(content, []byte("<")):
+		return FormatHTML
+	case bytes.HasPrefix(content, []byte("[")), bytes.HasPrefix(content, []byte("{")):
+		return FormatJSON
+	default:
+		return FormatCSV
+	}
+}
+
+// headerColumns maps lower case header names to columns of an owner.
+var headerColumns = map[string]int{
+	"code":       0,
+	"owner-code": 0,
+	"owner code": 0,
+	"bic-code":   0,
+	"bic code":   0,
+	"company":    1,
+	"city":       2,
+	"country":    3,
+}
+
+// parseOwnersCSV parses CSV separated by semicolons or commas. If the first record is
+// a header with the columns owner code, company, city and country, the columns are
+// used in the order of the header. Otherwise, the records have the columns in this order.
+func parseOwnersCSV(body io.Reader) ([]cont.Owner, error) {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	b = bytes.TrimPrefix(b, []byte("\ufeff"))
+
+	csvReader := csv.NewReader(bytes.NewReader(b))
+	csvReader.Comma = ';'
+	firstLine, _, _ := bytes.Cut(b, []byte("\n"))
+	if bytes.Count(firstLine, []byte(",")) > bytes.Count(firstLine, []byte(";")) {
+		csvReader.Comma = ','
+	}
+	csvReader.FieldsPerRecord = -1
+
+	columns := []int{0, 1, 2, 3}
+	var owners []cont.Owner
+	for record := 1; ; record++ {
+		rec, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if record == 1 {
+			if header, ok := headerIndexes(rec); ok {
+				columns = header
+				continue
+			}
+		}
+
+		fields := make([]string, len(columns))
+		for i, column := range columns {
+			if column >= len(rec) {
+				return nil, fmt.Errorf("record %d: has %d instead of at least %d fields", record, len(rec), column+1)
+			}
+			fields[i] = rec[column]
+		}
+		owner, err := newOwner(fields[0], fields[1], fields[2], fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", record, err)
+		}
+		owners = append(owners, owner)
+	}
+	if len(owners) == 0 {
+		return nil, fmt.Errorf("parsing CSV failed because no owner was parsed")
+	}
+	return owners, nil
+}
+
+// headerIndexes returns the indexes of the owner code, company, city and country
+// columns if rec is a header with all columns.
+func headerIndexes(rec []string) ([]int, bool) {
+	indexes := []int{-1, -1, -1, -1}
+	for i, name := range rec {
+		if column, ok := headerColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			indexes[column] = i
+		}
+	}
+	for _, index := range indexes {
+		if index == -1 {
+			return nil, false
+		}
+	}
+	return indexes, true
+}
+
+type jsonOwner struct {
+	Code      string `json:"code"`
+	OwnerCode string `json:"owner-code"`
+	Company   string `json:"company"`
+	City      string `json:"city"`
+	Country   string `json:"country"`
+}
+
+// parseOwnersJSON parses a JSON array of objects with the keys code or owner-code,
+// company, city and country.
+func parseOwnersJSON(body io.Reader) ([]cont.Owner, error) {
+	var jsonOwners []jsonOwner
+	if err := json.NewDecoder(body).Decode(&jsonOwners); err != nil {
+		return nil, err
+	}
+
+	owners := make([]cont.Owner, 0, len(jsonOwners))
+	for i, o := range jsonOwners {
+		owner, err := newOwner(cmp.Or(o.Code, o.OwnerCode), o.Company, o.City, o.Country)
+		if err != nil {
+			return nil, fmt.Errorf("object %d: %w", i+1, err)
+		}
+		owners = append(owners, owner)
+	}
+	if len(owners) == 0 {
+		return nil, fmt.Errorf("parsing JSON failed because no owner was parsed")
+	}
+	return owners, nil
+}
+
+// newOwner returns an owner like parsed from the downloaded page. A code can have
+// the equipment category ID like on the downloaded page and a country can be a
+// country code.
+func newOwner(code, company, city, country string) (cont.Owner, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == 4 {
+		code = code[0:3]
+	}
+	if err := cont.IsOwnerCode(code); err != nil {
+		return cont.Owner{}, err
+	}
+	country = strings.TrimSpace(country)
+	countryName, _ := data.CountryName(country)
+	return cont.Owner{
+		Code:    code,
+		Company: strings.TrimSpace(company),
+		City:    strings.TrimSpace(city),
+		Country: cmp.Or(countryName, country),
+	}, nil
+}
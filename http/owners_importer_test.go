@@ -0,0 +1,105 @@
+package http
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/meyermarcel/icm/cont"
+)
+
+func TestNewOwnersImporter(t *testing.T) {
+	want := []cont.Owner{
+		{Code: "AAA", Company: "A Company", City: "A City", Country: "A Country"},
+		{Code: "BBB", Company: "B Company", City: "B City", Country: "Germany"},
+	}
+
+	tests := []struct {
+		name    string
+		reader  io.Reader
+		format  string
+		want    []cont.Owner
+		wantErr bool
+	}{
+		{
+			"Import HTML like downloaded page",
+			validBody(),
+			FormatHTML,
+			[]cont.Owner{
+				{Code: "AAA", Company: "A Company", City: "A City", Country: "A Country"},
+				{Code: "BBB", Company: "B Company", City: "B City", Country: "B Country"},
+			},
+			false,
+		},
+		{
+			"Import HTML with detected format",
+			validBody(),
+			FormatAuto,
+			[]cont.Owner{
+				{Code: "AAA", Company: "A Company", City: "A City", Country: "A Country"},
+				{Code: "BBB", Company: "B Company", City: "B City", Country: "B Country"},
+			},
+			false,
+		},
+		{
+			"Import CSV without header like owner.csv",
+			strings.NewReader("AAA;A Company;A City;A Country\nBBB;B Company;B City;DE\n"),
+			FormatAuto,
+			want,
+			false,
+		},
+		{
+			"Import CSV with comma separated header in other order",
+			strings.NewReader("\ufeffBIC Code,Country,Company,City\nAAAU,A Country,A Company,A City\nBBBU,DE,\"B Company\",B City\n"),
+			FormatCSV,
+			want,
+			false,
+		},
+		{
+			"Import CSV with invalid owner code",
+			strings.NewReader("AAA;A Company;A City;A Country\nB1B;B Company;B City;DE\n"),
+			FormatCSV,
+			nil,
+			true,
+		},
+		{
+			"Import JSON",
+			strings.NewReader(` [{"code":"AAAU","company":"A Company","city":"A City","country":"A Country"},
+{"owner-code":"BBB","company":"B Company","city":"B City","country":"DE"}]`),
+			FormatAuto,
+			want,
+			false,
+		},
+		{
+			"Import empty JSON",
+			strings.NewReader(`[]`),
+			FormatJSON,
+			nil,
+			true,
+		},
+		{
+			"Import unknown format",
+			strings.NewReader(``),
+			"xml",
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []cont.Owner
+			importer, err := NewOwnersImporter(tt.reader, tt.format)
+			if err == nil {
+				got, err = importer.Download()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Download() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Download() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/meyermarcel/icm/cont"
+	"github.com/meyermarcel/icm/data"
+)
+
+const (
+	maxBodyBytes     = 10 << 20
+	maxBatchInputs   = 10000
+	maxGenerateCount = 100000
+)
+
+type server struct {
+	validator    *cont.Validator
+	ownerDecoder data.OwnerDecoder
+	// randMu guards rand because generation requests are handled concurrently.
+	// rand only seeds the pseudo random generator of every generation request.
+	randMu sync.Mutex
+	rand   *rand.Rand
+}
+
+// NewHandler returns a handler that serves validation, generation and owner lookup
+// with JSON responses. Following endpoints are available:
+//
+//	GET  /health
+//	POST /validate         {"input": "ABC U 123456 0"}
+//	POST /validate/batch   {"inputs": ["ABC U 123456 0", "ABCU1234560 22G1"]}
+//	GET  /generate         ?count=&start=&end=&owner=&exclude-check-digit-10=&exclude-error-prone-serial-numbers=&seed=
+//	GET  /owners/{code}
+func NewHandler(validator *cont.Validator, ownerDecoder data.OwnerDecoder, rand *rand.Rand) http.Handler {
+	s := &server{
+		validator:    validator,
+		ownerDecoder: ownerDecoder,
+		rand:         rand,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", s.health)
+	mux.HandleFunc("POST /validate", s.validate)
+	mux.HandleFunc("POST /validate/batch", s.validateBatch)
+	mux.HandleFunc("GET /generate", s.generate)
+	mux.HandleFunc("GET /owners/{code}", s.owner)
+	return mux
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type validateRequest struct {
+	Input string `json:"input"`
+}
+
+type validateBatchRequest struct {
+	Inputs []string `json:"inputs"`
+}
+
+type validateBatchResponse struct {
+	Results []validateResponse `json:"results"`
+}
+
+type validateResponse struct {
+	Input                      string                  `json:"input"`
+	Valid                      bool                    `json:"valid"`
+	OwnerCode                  string                  `json:"owner-code"`
+	Company                    string                  `json:"company,omitempty"`
+	City                       string                  `json:"city,omitempty"`
+	Country                    string                  `json:"country,omitempty"`
+	EquipCatID                 string                  `json:"equipment-category-id"`
+	EquipCat                   string                  `json:"equipment-category,omitempty"`
+	SerialNum                  string                  `json:"serial-number"`
+	CheckDigit                 string                  `json:"check-digit"`
+	CalcCheckDigit             *int                    `json:"calculated-check-digit"`
+	ValidCheckDigit            bool                    `json:"valid-check-digit"`
+	PossibleTranspositionError []string                `json:"possible-transposition-error"`
+	LengthCode                 string                  `json:"length-code,omitempty"`
+	LengthDesc                 string                  `json:"length-description,omitempty"`
+	HeightWidthCode            string                  `json:"height-width-code,omitempty"`
+	HeightDesc                 string                  `json:"height-description,omitempty"`
+	WidthDesc                  string                  `json:"width-description,omitempty"`
+	TypeCode                   string                  `json:"type-code,omitempty"`
+	TypeDesc                   string                  `json:"type-description,omitempty"`
+	GroupDesc                  string                  `json:"group-description,omitempty"`
+	Errors                     []validateErrorResponse `json:"errors"`
+}
+
+// validateErrorResponse is an error of a validated input. Code, part, value and
+// expected are the fields of a cont.ValidateError.
+type validateErrorResponse struct {
+	Code     string `json:"code"`
+	Part     string `json:"part"`
+	Value    string `json:"value"`
+	Expected string `json:"expected"`
+	Message  string `json:"message"`
+}
+
+type generateResponse struct {
+	Numbers []string `json:"numbers"`
+}
+
+type ownerResponse struct {
+	Code    string `json:"code"`
+	Company string `json:"company"`
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+func (s *server) health(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *server) validate(w http.ResponseWriter, r *http.Request) {
+	var req validateRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.validateInput(req.Input))
+}
+
+func (s *server) validateBatch(w http.ResponseWriter, r *http.Request) {
+	var req validateBatchRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Inputs) > maxBatchInputs {
+		writeError(w, http.StatusBadRequest,
+			fmt.Errorf("%d inputs exceed limit of %d inputs", len(req.Inputs), maxBatchInputs))
+		return
+	}
+	resp := validateBatchResponse{Results: make([]validateResponse, 0, len(req.Inputs))}
+	for _, in := range req.Inputs {
+		resp.Results = append(resp.Results, s.validateInput(in))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *server) validateInput(in string) validateResponse {
+	result := s.validator.Validate(in)
+
+	resp := validateResponse{
+		Input:           in,
+		Valid:           result.Valid(),
+		OwnerCode:       result.Owner.Code,
+		Company:         result.Owner.Owner.Company,
+		City:            result.Owner.Owner.City,
+		Country:         result.Owner.Owner.Country,
+		EquipCatID:      result.EquipCat.ID,
+		EquipCat:        result.EquipCat.EquipCat.Info,
+		SerialNum:       result.SerialNum.Value,
+		CheckDigit:      result.CheckDigit.Value,
+		ValidCheckDigit: result.CheckDigit.CalcCheckDigit != -1 && result.CheckDigit.Err == nil,
+		Errors:          []validateErrorResponse{},
+	}
+	if result.CheckDigit.CalcCheckDigit != -1 {
+		resp.CalcCheckDigit = &result.CheckDigit.CalcCheckDigit
+	}
+	for _, tpn := range result.CheckDigit.ErrorProneNumbers {
+		resp.PossibleTranspositionError = append(resp.PossibleTranspositionError, tpn.String())
+	}
+	if result.HasSizeType {
+		resp.LengthCode = result.Length.Code
+		resp.LengthDesc = string(result.Length.Length)
+		resp.HeightWidthCode = result.HeightWidth.Code
+		resp.HeightDesc = string(result.HeightWidth.Height)
+		resp.WidthDesc = string(result.HeightWidth.Width)
+		resp.TypeCode = result.Type.Code
+		resp.TypeDesc = string(result.Type.TypeInfo)
+		resp.GroupDesc = string(result.Type.GroupInfo)
+	}
+	for _, err := range result.Errors() {
+		errResp := validateErrorResponse{Message: err.Error()}
+		var validateErr *cont.ValidateError
+		if errors.As(err, &validateErr) {
+			errResp.Code = string(validateErr.Code)
+			errResp.Part = validateErr.Part
+			errResp.Value = validateErr.Value
+			errResp.Expected = validateErr.Expected
+		}
+		resp.Errors = append(resp.Errors, errResp)
+	}
+	return resp
+}
+
+func (s *server) generate(w http.ResponseWriter, r *http.Request) {
+	builder, err := s.newGeneratorBuilder(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	generator, err := builder.Build()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	resp := generateResponse{Numbers: []string{}}
+	for generator.Generate() {
+		if len(resp.Numbers) == maxGenerateCount {
+			writeError(w, http.StatusBadRequest,
+				fmt.Errorf("serial number range exceeds limit of %d", maxGenerateCount))
+			return
+		}
+		resp.Numbers = append(resp.Numbers, generator.ContNum().String())
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// newGeneratorBuilder returns a builder configured by the query parameters,
+// which have the same names as the flags of the generate command. Every builder
+// has its own pseudo random generator, so requests do not share state.
+func (s *server) newGeneratorBuilder(query url.Values) (*cont.GeneratorBuilder, error) {
+	var r *rand.Rand
+	if query.Has("seed") {
+		seed, err := strconv.ParseUint(query.Get("seed"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("seed: %w", err)
+		}
+		r = rand.New(rand.NewPCG(seed, 0))
+	} else {
+		s.randMu.Lock()
+		r = rand.New(rand.NewPCG(s.rand.Uint64(), s.rand.Uint64()))
+		s.randMu.Unlock()
+	}
+	builder := cont.NewUniqueGeneratorBuilder(r)
+
+	if query.Has("count") {
+		count, err := strconv.Atoi(query.Get("count"))
+		if err != nil {
+			return nil, fmt.Errorf("count: %w", err)
+		}
+		if count > maxGenerateCount {
+			return nil, fmt.Errorf("count %d exceeds limit of %d", count, maxGenerateCount)
+		}
+		builder.Count(count)
+	}
+	if query.Has("start") {
+		start, err := parseSerialNum(query.Get("start"))
+		if err != nil {
+			return nil, fmt.Errorf("start: %w", err)
+		}
+		builder.Start(start)
+	}
+	if query.Has("end") {
+		end, err := parseSerialNum(query.Get("end"))
+		if err != nil {
+			return nil, fmt.Errorf("end: %w", err)
+		}
+		builder.End(end)
+	}
+	if query.Has("owner") {
+		if err := cont.IsOwnerCode(query.Get("owner")); err != nil {
+			return nil, err
+		}
+		builder.OwnerCodes([]string{query.Get("owner")})
+	} else {
+		builder.OwnerCodes(s.ownerDecoder.GetAllOwnerCodes())
+	}
+	if query.Has("exclude-check-digit-10") {
+		exclude, err := strconv.ParseBool(query.Get("exclude-check-digit-10"))
+		if err != nil {
+			return nil, fmt.Errorf("exclude-check-digit-10: %w", err)
+		}
+		builder.ExcludeCheckDigit10(exclude)
+	}
+	if query.Has("exclude-error-prone-serial-numbers") {
+		exclude, err := strconv.ParseBool(query.Get("exclude-error-prone-serial-numbers"))
+		if err != nil {
+			return nil, fmt.Errorf("exclude-error-prone-serial-numbers: %w", err)
+		}
+		builder.ExcludeErrorProneSerialNumbers(exclude)
+	}
+	return builder, nil
+}
+
+func parseSerialNum(value string) (int, error) {
+	serialNum, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if serialNum < 0 || serialNum > 999999 {
+		return 0, fmt.Errorf("%d is not in range from 0 to 999999", serialNum)
+	}
+	return serialNum, nil
+}
+
+func (s *server) owner(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	if err := cont.IsOwnerCode(code); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	found, owner := s.ownerDecoder.Decode(code)
+	if !found {
+		writeError(w, http.StatusNotFound, cont.ErrOwnerNotRegistered)
+		return
+	}
+	writeJSON(w, http.StatusOK, ownerResponse{
+		Code:    owner.Code,
+		Company: owner.Company,
+		City:    owner.City,
+		Country: owner.Country,
+	})
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	if decoder.More() {
+		return errors.New("invalid request body: more than one JSON value")
+	}
+	return nil
+}
+
+func writeError(w http.ResponseWriter, statusCode int, err error) {
+	writeJSON(w, statusCode, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,214 @@
+package http
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/meyermarcel/icm/cont"
+)
+
+type dummyOwnerDecoder struct{}
+
+func (dummyOwnerDecoder) Decode(code string) (bool, cont.Owner) {
+	if code != "ABC" {
+		return false, cont.Owner{}
+	}
+	return true, cont.Owner{Code: "ABC", Company: "some-company", City: "some-city", Country: "some-country"}
+}
+
+func (dummyOwnerDecoder) GetAllOwnerCodes() []string {
+	return []string{"ABC"}
+}
+
+func (dummyOwnerDecoder) Source(_ string) string {
+	return ""
+}
+
+func (dummyOwnerDecoder) GetAllOwners() []cont.Owner {
+	return []cont.Owner{{Code: "ABC", Company: "some-company", City: "some-city", Country: "some-country"}}
+}
+
+type dummyEquipCatDecoder struct{}
+
+func (dummyEquipCatDecoder) Decode(ID string) (bool, cont.EquipCat) {
+	return ID == "U", cont.EquipCat{Value: ID, Info: "some-equip-cat"}
+}
+
+type dummyLengthDecoder struct{}
+
+func (dummyLengthDecoder) Decode(string) (bool, cont.Length) {
+	return true, "some-length"
+}
+
+type dummyHeightWidthDecoder struct{}
+
+func (dummyHeightWidthDecoder) Decode(string) (bool, cont.Height, cont.Width) {
+	return true, "some-height", "some-width"
+}
+
+type dummyTypeDecoder struct{}
+
+func (dummyTypeDecoder) Decode(string) (bool, cont.TypeInfo, cont.GroupInfo) {
+	return true, "some-type", "some-group"
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			"Health",
+			http.MethodGet,
+			"/health",
+			"",
+			http.StatusOK,
+			`{"status":"ok"}`,
+		},
+		{
+			"Validate valid container number",
+			http.MethodPost,
+			"/validate",
+			`{"input": "abc u 681304 0"}`,
+			http.StatusOK,
+			`{"input":"abc u 681304 0","valid":true,"owner-code":"ABC","company":"some-company","city":"some-city","country":"some-country","equipment-category-id":"U","equipment-category":"some-equip-cat","serial-number":"681304","check-digit":"0","calculated-check-digit":0,"valid-check-digit":true,"possible-transposition-error":["ABCU6810340","ABCU6813400"],"errors":[]}`,
+		},
+		{
+			"Validate invalid container number with size and type",
+			http.MethodPost,
+			"/validate",
+			`{"input": "XYZU1231231 22G1"}`,
+			http.StatusOK,
+			`{"input":"XYZU1231231 22G1","valid":false,"owner-code":"XYZ","equipment-category-id":"U","equipment-category":"some-equip-cat","serial-number":"123123","check-digit":"1","calculated-check-digit":7,"valid-check-digit":false,"possible-transposition-error":null,"length-code":"2","length-description":"some-length","height-width-code":"2","height-description":"some-height","width-description":"some-width","type-code":"G1","type-description":"some-type","group-description":"some-group","errors":[{"code":"OWNER_NOT_REGISTERED","part":"owner-code","value":"XYZ","expected":"registered owner code","message":"owner code is not registered"},{"code":"CHECK_DIGIT_MISMATCH","part":"check-digit","value":"1","expected":"7","message":"check digit is not the calculated check digit"}]}`,
+		},
+		{
+			"Validate with invalid body",
+			http.MethodPost,
+			"/validate",
+			`{"in": "ABC"}`,
+			http.StatusBadRequest,
+			`{"error":"invalid request body: json: unknown field \"in\""}`,
+		},
+		{
+			"Validate batch",
+			http.MethodPost,
+			"/validate/batch",
+			`{"inputs": ["ABCU1234560", "ABC"]}`,
+			http.StatusOK,
+			`{"results":[{"input":"ABCU1234560","valid":true,"owner-code":"ABC","company":"some-company","city":"some-city","country":"some-country","equipment-category-id":"U","equipment-category":"some-equip-cat","serial-number":"123456","check-digit":"0","calculated-check-digit":0,"valid-check-digit":true,"possible-transposition-error":null,"errors":[]},{"input":"ABC","valid":false,"owner-code":"ABC","company":"some-company","city":"some-city","country":"some-country","equipment-category-id":"","serial-number":"","check-digit":"","calculated-check-digit":null,"valid-check-digit":false,"possible-transposition-error":null,"errors":[{"code":"EQUIP_CAT_FORMAT","part":"equipment-category-id","value":"","expected":"1 letter","message":"equipment category id is not 1 letter long"},{"code":"SERIAL_FORMAT","part":"serial-number","value":"","expected":"6 numbers","message":"serial number is not 6 numbers long"},{"code":"CHECK_DIGIT_NOT_CALCULABLE","part":"check-digit","value":"","expected":"","message":"check digit is not calculable"}]}]}`,
+		},
+		{
+			"Validate with wrong method",
+			http.MethodGet,
+			"/validate",
+			"",
+			http.StatusMethodNotAllowed,
+			`Method Not Allowed`,
+		},
+		{
+			"Generate with sequential serial numbers",
+			http.MethodGet,
+			"/generate?start=0&count=3&exclude-check-digit-10=true",
+			"",
+			http.StatusOK,
+			`{"numbers":["ABCU0000001","ABCU0000017","ABCU0000022"]}`,
+		},
+		{
+			"Generate with invalid owner",
+			http.MethodGet,
+			"/generate?owner=abc",
+			"",
+			http.StatusBadRequest,
+			`{"error":"abc is not 3 upper case letters"}`,
+		},
+		{
+			"Generate with invalid start",
+			http.MethodGet,
+			"/generate?start=1000000",
+			"",
+			http.StatusBadRequest,
+			`{"error":"start: 1000000 is not in range from 0 to 999999"}`,
+		},
+		{
+			"Look up owner",
+			http.MethodGet,
+			"/owners/ABC",
+			"",
+			http.StatusOK,
+			`{"code":"ABC","company":"some-company","city":"some-city","country":"some-country"}`,
+		},
+		{
+			"Look up unknown owner",
+			http.MethodGet,
+			"/owners/XYZ",
+			"",
+			http.StatusNotFound,
+			`{"error":"owner code is not registered"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := cont.NewValidator(
+				dummyOwnerDecoder{},
+				dummyEquipCatDecoder{},
+				dummyLengthDecoder{},
+				dummyHeightWidthDecoder{},
+				dummyTypeDecoder{},
+			)
+			handler := NewHandler(validator, dummyOwnerDecoder{}, rand.New(rand.NewPCG(1, 0)))
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("status code = %v, want %v", rec.Code, tt.wantStatusCode)
+			}
+			if gotBody := strings.TrimSpace(rec.Body.String()); gotBody != tt.wantBody {
+				t.Errorf("body = %v, want %v", gotBody, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestHandlerGenerateConcurrently(t *testing.T) {
+	validator := cont.NewValidator(
+		dummyOwnerDecoder{},
+		dummyEquipCatDecoder{},
+		dummyLengthDecoder{},
+		dummyHeightWidthDecoder{},
+		dummyTypeDecoder{},
+	)
+	handler := NewHandler(validator, dummyOwnerDecoder{}, rand.New(rand.NewPCG(1, 0)))
+
+	bodies := make([]string, 8)
+	var wg sync.WaitGroup
+	for i := range bodies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodGet, "/generate?count=5&seed=42", nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			bodies[i] = rec.Body.String()
+		}()
+	}
+	wg.Wait()
+
+	for _, body := range bodies {
+		if body != bodies[0] {
+			t.Errorf("body = %v, want %v for same seed", body, bodies[0])
+		}
+	}
+	if !strings.Contains(bodies[0], `"numbers":["ABCU`) {
+		t.Errorf("body = %v, want generated numbers", bodies[0])
+	}
+}
@@ -2,12 +2,15 @@ package input
 
 import (
 	"encoding/csv"
+	"strconv"
+	"strings"
 )
 
-// Datum represents a datum that is be used by CSVPrinter.
+// Datum represents a datum that is be used by CSVPrinter and JSONPrinter.
 type Datum struct {
 	header string
 	value  string
+	typed  any
 }
 
 // NewDatum returns a new Datum.
@@ -15,16 +18,70 @@ func NewDatum(header string) Datum {
 	return Datum{header: header}
 }
 
+// Header returns the header.
+func (d Datum) Header() string {
+	return d.header
+}
+
+// Value returns the value as formatted for CSV output.
+func (d Datum) Value() string {
+	return d.value
+}
+
 // WithValue sets value and returns Datum.
 func (d Datum) WithValue(value string) Datum {
 	d.value = value
 	return d
 }
 
+// WithBool sets a boolean value and returns Datum.
+func (d Datum) WithBool(value bool) Datum {
+	d.value = strconv.FormatBool(value)
+	d.typed = value
+	return d
+}
+
+// WithInt sets an integer value and returns Datum.
+func (d Datum) WithInt(value int) Datum {
+	d.value = strconv.Itoa(value)
+	d.typed = value
+	return d
+}
+
+// NewListDatum returns a new Datum for multiple values. Without values
+// JSONPrinter prints an empty array instead of null.
+func NewListDatum(header string) Datum {
+	return Datum{header: header, typed: []string{}}
+}
+
+// WithValues sets multiple values and returns Datum.
+// CSVPrinter joins the values with a comma.
+func (d Datum) WithValues(values []string) Datum {
+	if values == nil {
+		values = []string{}
+	}
+	d.value = strings.Join(values, ", ")
+	d.typed = values
+	return d
+}
+
+// jsonValue returns the typed value if set, otherwise the string value.
+// nil is returned for a Datum without value.
+func (d Datum) jsonValue() any {
+	if d.typed != nil {
+		return d.typed
+	}
+	if d.value == "" {
+		return nil
+	}
+	return d.value
+}
+
 // CSVPrinter prints the set record. Use SetRecord to set a record.
 type CSVPrinter struct {
 	csvWriter     *csv.Writer
 	headers       []string
+	fixedHeaders  []string
 	record        []string
 	headerPrinted bool
 	noHeader      bool
@@ -38,16 +95,37 @@ func NewCSVPrinter(csvWriter *csv.Writer, noHeader bool) *CSVPrinter {
 	}
 }
 
-// Print writes set record to passed writer.
+// SetHeaders sets a fixed header. Values of every record are aligned to the
+// headers and values of missing headers are empty. Data with headers not in
+// headers is omitted. This keeps the columns stable if inputs of different
+// patterns are printed.
+func (cp *CSVPrinter) SetHeaders(headers []string) {
+	cp.fixedHeaders = headers
+}
+
+// Print writes set record to passed writer. Records are buffered, use Flush to write them.
 // No header is printed if noHeader is set to false.
 // Print returns an error if writing to writer fails.
 func (cp *CSVPrinter) Print(inputs []Input) error {
 	cp.headers = nil
 	cp.record = nil
-	for _, input := range inputs {
-		for _, datum := range input.data {
-			cp.headers = append(cp.headers, datum.header)
-			cp.record = append(cp.record, datum.value)
+	if cp.fixedHeaders != nil {
+		values := map[string]string{}
+		for _, input := range inputs {
+			for _, datum := range input.data {
+				values[datum.header] = datum.value
+			}
+		}
+		cp.headers = cp.fixedHeaders
+		for _, header := range cp.fixedHeaders {
+			cp.record = append(cp.record, values[header])
+		}
+	} else {
+		for _, input := range inputs {
+			for _, datum := range input.data {
+				cp.headers = append(cp.headers, datum.header)
+				cp.record = append(cp.record, datum.value)
+			}
 		}
 	}
 
@@ -58,10 +136,11 @@ func (cp *CSVPrinter) Print(inputs []Input) error {
 		}
 		cp.headerPrinted = true
 	}
-	err := cp.csvWriter.Write(cp.record)
-	if err != nil {
-		return err
-	}
+	return cp.csvWriter.Write(cp.record)
+}
+
+// Flush writes buffered records to the underlying writer.
+func (cp *CSVPrinter) Flush() error {
 	cp.csvWriter.Flush()
-	return nil
+	return cp.csvWriter.Error()
 }
@@ -10,6 +10,7 @@ func TestCSVPrinter_Print(t *testing.T) {
 	tests := []struct {
 		name       string
 		noHeader   bool
+		headers    []string
 		inputs     []Input
 		wantWriter string
 	}{
@@ -41,6 +42,23 @@ value-1,value-2,value-3
 				},
 			},
 			wantWriter: `value-1,value-2
+`,
+		},
+		{
+			name:     "Print CSV with headers",
+			noHeader: false,
+			headers:  []string{"header-0", "header-2", "header-1"},
+			inputs: []Input{
+				{
+					data: []Datum{
+						{header: "header-1", value: "value-1"},
+						{header: "header-2", value: "value-2"},
+						{header: "header-3", value: "value-3"},
+					},
+				},
+			},
+			wantWriter: `header-0,header-2,header-1
+,value-2,value-1
 `,
 		},
 	}
@@ -50,6 +68,9 @@ value-1,value-2,value-3
 
 			csvWriter := csv.NewWriter(writer)
 			csvPrinter := NewCSVPrinter(csvWriter, tt.noHeader)
+			if tt.headers != nil {
+				csvPrinter.SetHeaders(tt.headers)
+			}
 			_ = csvPrinter.Print(tt.inputs)
 
 			csvWriter.Flush()
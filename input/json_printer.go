@@ -0,0 +1,126 @@
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+)
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// JSONError is an object of the errors array of a printed JSON object.
+type JSONError struct {
+	Code     string `json:"code"`
+	Part     string `json:"part"`
+	Value    string `json:"value"`
+	Expected string `json:"expected"`
+	// Message is the error message without ANSI escape codes.
+	Message string `json:"message"`
+}
+
+// JSONPrinter prints inputs as JSON objects. Use NewJSONPrinter or
+// NewNDJSONPrinter to instantiate one.
+type JSONPrinter struct {
+	jsonWriter *JSONWriter
+	headers    []string
+	errorFunc  func(err error) JSONError
+}
+
+// NewJSONPrinter creates a JSONPrinter that writes a single JSON document.
+// The document is an array with an object for every printed line and is
+// completed by Close.
+func NewJSONPrinter(writer io.Writer) *JSONPrinter {
+	return &JSONPrinter{jsonWriter: NewJSONWriter(writer)}
+}
+
+// NewNDJSONPrinter creates a JSONPrinter that writes one JSON object per line.
+func NewNDJSONPrinter(writer io.Writer) *JSONPrinter {
+	return &JSONPrinter{jsonWriter: NewNDJSONWriter(writer)}
+}
+
+// SetHeaders sets fixed keys. Every object has all keys in order of headers
+// and keys of missing data are null. Data with headers not in headers is omitted.
+func (jp *JSONPrinter) SetHeaders(headers []string) {
+	jp.headers = headers
+}
+
+// SetErrorFunc sets a function that returns the code, the part and the values
+// of an error. The message is always set by the printer. Without a function
+// only the message of an error is set.
+func (jp *JSONPrinter) SetErrorFunc(errorFunc func(err error) JSONError) {
+	jp.errorFunc = errorFunc
+}
+
+// Print writes an object with the data of inputs to writer.
+// Every error of inputs is added to the errors array of the object.
+func (jp *JSONPrinter) Print(inputs []Input) error {
+	b, err := marshalInputs(inputs, jp.headers, jp.errorFunc)
+	if err != nil {
+		return err
+	}
+	return jp.jsonWriter.WriteObject(b)
+}
+
+// Close completes the JSON document. For NDJSON output nothing is written.
+func (jp *JSONPrinter) Close() error {
+	return jp.jsonWriter.Close()
+}
+
+// marshalInputs returns a JSON object with the data of inputs in the
+// order of the inputs or in the order of headers if headers is not nil.
+func marshalInputs(inputs []Input, headers []string, errorFunc func(err error) JSONError) ([]byte, error) {
+	b := &bytes.Buffer{}
+	b.WriteString("{")
+
+	errs := []JSONError{}
+	values := map[string]any{}
+	for _, input := range inputs {
+		for _, datum := range input.data {
+			if headers != nil {
+				values[datum.header] = datum.jsonValue()
+				continue
+			}
+			if err := writeField(b, datum.header, datum.jsonValue()); err != nil {
+				return nil, err
+			}
+		}
+		if input.err != nil {
+			var jsonErr JSONError
+			if errorFunc != nil {
+				jsonErr = errorFunc(input.err)
+			}
+			jsonErr.Message = ansiEscape.ReplaceAllString(input.err.Error(), "")
+			errs = append(errs, jsonErr)
+		}
+	}
+	for _, header := range headers {
+		if err := writeField(b, header, values[header]); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeField(b, "errors", errs); err != nil {
+		return nil, err
+	}
+
+	b.WriteString("}")
+	return b.Bytes(), nil
+}
+
+func writeField(b *bytes.Buffer, key string, value any) error {
+	if b.Len() > 1 {
+		b.WriteString(",")
+	}
+	k, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	v, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	b.Write(k)
+	b.WriteString(":")
+	b.Write(v)
+	return nil
+}
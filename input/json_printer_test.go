@@ -0,0 +1,112 @@
+package input
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestJSONPrinter_Print(t *testing.T) {
+	lines := [][]Input{
+		{
+			{
+				data: []Datum{
+					NewDatum("header-1").WithValue("value-1"),
+					NewDatum("header-2"),
+				},
+			},
+			{
+				err: errors.New("\x1b[4merror\x1b[0m 1"),
+				data: []Datum{
+					NewDatum("header-3").WithBool(true),
+					NewDatum("header-4").WithInt(10),
+					NewDatum("header-5").WithValues([]string{"value-5", "value-6"}),
+					NewListDatum("header-6"),
+				},
+			},
+		},
+		{
+			{
+				err:  errors.New("error 2"),
+				data: []Datum{NewDatum("header-1").WithValue("value-7")},
+			},
+			{
+				err: errors.New("error 3"),
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		printer    func(buffer *bytes.Buffer) *JSONPrinter
+		headers    []string
+		lines      [][]Input
+		wantWriter string
+	}{
+		{
+			name:    "Print JSON document",
+			printer: func(buffer *bytes.Buffer) *JSONPrinter { return NewJSONPrinter(buffer) },
+			lines:   lines,
+			wantWriter: `[
+{"header-1":"value-1","header-2":null,"header-3":true,"header-4":10,"header-5":["value-5","value-6"],"header-6":[],"errors":[{"code":"","part":"","value":"","expected":"","message":"error 1"}]},
+{"header-1":"value-7","errors":[{"code":"","part":"","value":"","expected":"","message":"error 2"},{"code":"","part":"","value":"","expected":"","message":"error 3"}]}
+]
+`,
+		},
+		{
+			name:       "Print empty JSON document",
+			printer:    func(buffer *bytes.Buffer) *JSONPrinter { return NewJSONPrinter(buffer) },
+			wantWriter: "[]\n",
+		},
+		{
+			name:    "Print NDJSON",
+			printer: func(buffer *bytes.Buffer) *JSONPrinter { return NewNDJSONPrinter(buffer) },
+			lines:   lines,
+			wantWriter: `{"header-1":"value-1","header-2":null,"header-3":true,"header-4":10,"header-5":["value-5","value-6"],"header-6":[],"errors":[{"code":"","part":"","value":"","expected":"","message":"error 1"}]}
+{"header-1":"value-7","errors":[{"code":"","part":"","value":"","expected":"","message":"error 2"},{"code":"","part":"","value":"","expected":"","message":"error 3"}]}
+`,
+		},
+		{
+			name: "Print NDJSON with error func",
+			printer: func(buffer *bytes.Buffer) *JSONPrinter {
+				jp := NewNDJSONPrinter(buffer)
+				jp.SetErrorFunc(func(error) JSONError {
+					return JSONError{Code: "CODE", Part: "part", Value: "value", Expected: "expected", Message: "ignored"}
+				})
+				return jp
+			},
+			lines: lines[1:],
+			wantWriter: `{"header-1":"value-7","errors":[{"code":"CODE","part":"part","value":"value","expected":"expected","message":"error 2"},{"code":"CODE","part":"part","value":"value","expected":"expected","message":"error 3"}]}
+`,
+		},
+		{
+			name:    "Print NDJSON with headers",
+			printer: func(buffer *bytes.Buffer) *JSONPrinter { return NewNDJSONPrinter(buffer) },
+			headers: []string{"header-0", "header-4", "header-1"},
+			lines:   lines,
+			wantWriter: `{"header-0":null,"header-4":10,"header-1":"value-1","errors":[{"code":"","part":"","value":"","expected":"","message":"error 1"}]}
+{"header-0":null,"header-4":null,"header-1":"value-7","errors":[{"code":"","part":"","value":"","expected":"","message":"error 2"},{"code":"","part":"","value":"","expected":"","message":"error 3"}]}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+			jp := tt.printer(writer)
+			if tt.headers != nil {
+				jp.SetHeaders(tt.headers)
+			}
+			for _, inputs := range tt.lines {
+				if err := jp.Print(inputs); err != nil {
+					t.Errorf("JSONPrinter.Print() error = %v", err)
+				}
+			}
+			if err := jp.Close(); err != nil {
+				t.Errorf("JSONPrinter.Close() error = %v", err)
+			}
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("JSONPrinter.Print() = %v, want %v", gotWriter, tt.wantWriter)
+			}
+		})
+	}
+}
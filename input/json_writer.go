@@ -0,0 +1,58 @@
+package input
+
+import "io"
+
+// JSONWriter writes JSON objects as a single JSON document or as newline
+// delimited JSON. Use NewJSONWriter or NewNDJSONWriter to instantiate one.
+type JSONWriter struct {
+	writer  io.Writer
+	ndjson  bool
+	written bool
+}
+
+// NewJSONWriter creates a JSONWriter that writes a single JSON document.
+// The document is an array with all written objects and is completed by Close.
+func NewJSONWriter(writer io.Writer) *JSONWriter {
+	return &JSONWriter{writer: writer}
+}
+
+// NewNDJSONWriter creates a JSONWriter that writes one JSON object per line.
+func NewNDJSONWriter(writer io.Writer) *JSONWriter {
+	return &JSONWriter{writer: writer, ndjson: true}
+}
+
+// WriteObject writes the encoded JSON object to writer.
+func (jw *JSONWriter) WriteObject(object []byte) error {
+	var prefix, suffix string
+	switch {
+	case jw.ndjson:
+		suffix = "\n"
+	case jw.written:
+		prefix = ",\n"
+	default:
+		prefix = "[\n"
+	}
+	jw.written = true
+
+	if _, err := io.WriteString(jw.writer, prefix); err != nil {
+		return err
+	}
+	if _, err := jw.writer.Write(object); err != nil {
+		return err
+	}
+	_, err := io.WriteString(jw.writer, suffix)
+	return err
+}
+
+// Close completes the JSON document. For NDJSON output nothing is written.
+func (jw *JSONWriter) Close() error {
+	if jw.ndjson {
+		return nil
+	}
+	if !jw.written {
+		_, err := io.WriteString(jw.writer, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(jw.writer, "\n]\n")
+	return err
+}
@@ -0,0 +1,58 @@
+package input
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestJSONWriter(t *testing.T) {
+	tests := []struct {
+		name       string
+		jsonWriter func(writer io.Writer) *JSONWriter
+		objects    []string
+		wantWriter string
+	}{
+		{
+			"Write JSON document",
+			NewJSONWriter,
+			[]string{`{"a":1}`, `{"a":2}`},
+			"[\n{\"a\":1},\n{\"a\":2}\n]\n",
+		},
+		{
+			"Write empty JSON document",
+			NewJSONWriter,
+			nil,
+			"[]\n",
+		},
+		{
+			"Write NDJSON",
+			NewNDJSONWriter,
+			[]string{`{"a":1}`, `{"a":2}`},
+			"{\"a\":1}\n{\"a\":2}\n",
+		},
+		{
+			"Write empty NDJSON",
+			NewNDJSONWriter,
+			nil,
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &bytes.Buffer{}
+			jw := tt.jsonWriter(writer)
+			for _, object := range tt.objects {
+				if err := jw.WriteObject([]byte(object)); err != nil {
+					t.Fatalf("WriteObject() error = %v", err)
+				}
+			}
+			if err := jw.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if gotWriter := writer.String(); gotWriter != tt.wantWriter {
+				t.Errorf("gotWriter = %q, want %q", gotWriter, tt.wantWriter)
+			}
+		})
+	}
+}
@@ -3,7 +3,13 @@ package input
 // Match returns pattern if all values are valid formatted. If no pattern
 // meets the requirement the first pattern is returned.
 func Match(in string, patterns [][]func() Input) []func() Input {
-	for _, newInputs := range patterns {
+	return patterns[MatchIndex(in, patterns)]
+}
+
+// MatchIndex returns the index of the first pattern with all values valid
+// formatted. If no pattern meets the requirement 0 is returned.
+func MatchIndex(in string, patterns [][]func() Input) int {
+	for idx, newInputs := range patterns {
 		inTemp := in
 		allValidFmt := true
 		for _, newInput := range newInputs {
@@ -16,8 +22,8 @@ func Match(in string, patterns [][]func() Input) []func() Input {
 			allValidFmt = allValidFmt && input.isValidFmt()
 		}
 		if allValidFmt {
-			return newInputs
+			return idx
 		}
 	}
-	return patterns[0]
+	return 0
 }
@@ -0,0 +1,173 @@
+package input
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultBatchSize = 256
+	readerSize       = 64 * 1024
+)
+
+// Pipeline validates lines concurrently with a bounded number of workers and prints
+// the inputs in order of the lines. Lines are validated in batches and a Printer that
+// also implements Flusher is flushed after every batch.
+type Pipeline struct {
+	validate  func(line string) ([]Input, error)
+	printer   Printer
+	onResult  func(inputs []Input, err error)
+	workers   int
+	batchSize int
+}
+
+// Flusher is implemented by a Printer that buffers printed inputs.
+type Flusher interface {
+	Flush() error
+}
+
+// NewPipeline returns a new Pipeline that validates lines with validate and prints
+// the returned inputs with printer. validate must be safe for concurrent use.
+// By default a worker is used for every CPU.
+func NewPipeline(validate func(line string) ([]Input, error), printer Printer) *Pipeline {
+	return &Pipeline{
+		validate:  validate,
+		printer:   printer,
+		workers:   runtime.GOMAXPROCS(0),
+		batchSize: defaultBatchSize,
+	}
+}
+
+// SetResultFunc sets a function that is called with the result of every line in order
+// of the lines before the inputs are printed.
+func (p *Pipeline) SetResultFunc(onResult func(inputs []Input, err error)) {
+	p.onResult = onResult
+}
+
+// SetWorkers sets the count of workers that validate lines concurrently.
+func (p *Pipeline) SetWorkers(workers int) {
+	p.workers = max(workers, 1)
+}
+
+// SetBatchSize sets the maximum count of lines of a batch.
+func (p *Pipeline) SetBatchSize(batchSize int) {
+	p.batchSize = max(batchSize, 1)
+}
+
+type result struct {
+	inputs []Input
+	err    error
+}
+
+type batch struct {
+	lines   []string
+	results []result
+	done    chan struct{}
+}
+
+// Run validates and prints all lines of reader. Run returns the first error of
+// printing or reading. Validation errors are passed to the function set by SetResultFunc.
+func (p *Pipeline) Run(reader io.Reader) error {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	jobs := make(chan *batch)
+	// ordered has the batches in order of the lines and bounds the batches in progress.
+	ordered := make(chan *batch, p.workers)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(jobs)
+		defer close(ordered)
+		readErr <- p.readBatches(reader, func(b *batch) bool {
+			select {
+			case ordered <- b:
+			case <-stop:
+				return false
+			}
+			select {
+			case jobs <- b:
+			case <-stop:
+				return false
+			}
+			return true
+		})
+	}()
+
+	var wg sync.WaitGroup
+	for range p.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range jobs {
+				for i, line := range b.lines {
+					b.results[i].inputs, b.results[i].err = p.validate(line)
+				}
+				close(b.done)
+			}
+		}()
+	}
+
+	for b := range ordered {
+		<-b.done
+		for _, r := range b.results {
+			if p.onResult != nil {
+				p.onResult(r.inputs, r.err)
+			}
+			if err := p.printer.Print(r.inputs); err != nil {
+				return err
+			}
+		}
+		if flusher, ok := p.printer.(Flusher); ok {
+			if err := flusher.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	wg.Wait()
+
+	return <-readErr
+}
+
+// readBatches reads lines of reader into batches up to the batch size and passes every
+// batch to send until send returns false. A batch is also passed if no more input is
+// buffered, so lines of slow input like an interactive terminal are printed without delay.
+func (p *Pipeline) readBatches(reader io.Reader, send func(b *batch) bool) error {
+	bufReader, ok := reader.(*bufio.Reader)
+	if !ok {
+		bufReader = bufio.NewReaderSize(reader, readerSize)
+	}
+
+	lines := make([]string, 0, p.batchSize)
+	sendLines := func() bool {
+		if len(lines) == 0 {
+			return true
+		}
+		b := &batch{lines: lines, results: make([]result, len(lines)), done: make(chan struct{})}
+		lines = make([]string, 0, p.batchSize)
+		return send(b)
+	}
+
+	for {
+		line, err := bufReader.ReadString('\n')
+		if line != "" {
+			line = strings.TrimSuffix(line, "\n")
+			lines = append(lines, strings.TrimSuffix(line, "\r"))
+		}
+		if err != nil {
+			sendLines()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if len(lines) == p.batchSize || bufReader.Buffered() == 0 {
+			if !sendLines() {
+				return nil
+			}
+		}
+	}
+}
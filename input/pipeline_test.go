@@ -0,0 +1,113 @@
+package input
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type recordingPrinter struct {
+	lines   []string
+	flushes int
+	err     error
+}
+
+func (rp *recordingPrinter) Print(inputs []Input) error {
+	if rp.err != nil {
+		return rp.err
+	}
+	rp.lines = append(rp.lines, inputs[0].value)
+	return nil
+}
+
+func (rp *recordingPrinter) Flush() error {
+	rp.flushes++
+	return nil
+}
+
+func validateLine(line string) ([]Input, error) {
+	var err error
+	if strings.HasPrefix(line, "invalid") {
+		err = errors.New(line)
+	}
+	return []Input{{value: line}}, err
+}
+
+func TestPipeline_Run(t *testing.T) {
+	var lines []string
+	for i := range 1000 {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+
+	tests := []struct {
+		name      string
+		lines     []string
+		workers   int
+		batchSize int
+		printErr  error
+		wantErr   error
+		// wantResults are the validation errors passed to the result function in order.
+		wantResults []string
+	}{
+		{"Print lines in order with many workers", lines, 8, 3, nil, nil, nil},
+		{"Print lines in order with one worker", lines, 1, 1024, nil, nil, nil},
+		{"Print no lines", nil, 4, 16, nil, nil, nil},
+		{
+			"Pass validation errors in order",
+			[]string{"invalid 1", "line 2", "invalid 3"},
+			4, 1, nil, nil,
+			[]string{"invalid 1", "<nil>", "invalid 3"},
+		},
+		{"Return print error", lines, 4, 16, errors.New("print error"), errors.New("print error"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			printer := &recordingPrinter{err: tt.printErr}
+			pipeline := NewPipeline(validateLine, printer)
+			pipeline.SetWorkers(tt.workers)
+			pipeline.SetBatchSize(tt.batchSize)
+			var results []string
+			pipeline.SetResultFunc(func(_ []Input, err error) {
+				results = append(results, fmt.Sprint(err))
+			})
+
+			err := pipeline.Run(strings.NewReader(strings.Join(tt.lines, "\n")))
+			if fmt.Sprint(err) != fmt.Sprint(tt.wantErr) {
+				t.Errorf("Run() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.printErr != nil {
+				return
+			}
+			if strings.Join(printer.lines, "\n") != strings.Join(tt.lines, "\n") {
+				t.Errorf("Run() printed %d lines not in order of %d lines", len(printer.lines), len(tt.lines))
+			}
+			if tt.wantResults != nil && strings.Join(results, ",") != strings.Join(tt.wantResults, ",") {
+				t.Errorf("Run() results = %v, want %v", results, tt.wantResults)
+			}
+			if len(results) != len(tt.lines) {
+				t.Errorf("Run() passed %d results for %d lines", len(results), len(tt.lines))
+			}
+			if len(tt.lines) > 0 && printer.flushes == 0 {
+				t.Errorf("Run() did not flush printer")
+			}
+		})
+	}
+}
+
+func TestPipeline_RunWithCSVPrinter(t *testing.T) {
+	writer := &bytes.Buffer{}
+	csvWriter := csv.NewWriter(writer)
+	pipeline := NewPipeline(func(line string) ([]Input, error) {
+		return []Input{{data: []Datum{NewDatum("header").WithValue(line)}}}, nil
+	}, NewCSVPrinter(csvWriter, false))
+
+	if err := pipeline.Run(strings.NewReader("a\r\nb\nc")); err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+	if want := "header\na\nb\nc\n"; writer.String() != want {
+		t.Errorf("Run() = %v, want %v", writer.String(), want)
+	}
+}
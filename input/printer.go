@@ -1,6 +1,9 @@
 package input
 
 // Printer prints inputs and returns nil if no error occurred.
+// A Printer that also implements io.Closer must be closed after
+// the last Print call to complete the output. A Printer that also
+// implements Flusher must be flushed after the last Print call.
 type Printer interface {
 	Print(inputs []Input) error
 }
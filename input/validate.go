@@ -36,6 +36,17 @@ func Validate(in string, newInputs []func() Input) ([]Input, error) {
 	return inputs, err
 }
 
+// Headers returns the headers of the data of inputs in order.
+func Headers(inputs []Input) []string {
+	var headers []string
+	for _, input := range inputs {
+		for _, datum := range input.data {
+			headers = append(headers, datum.header)
+		}
+	}
+	return headers
+}
+
 // Input is a structured part of an input string.
 type Input struct {
 	runeCount      int
@@ -62,6 +73,31 @@ func NewInput(runeCount int,
 	return Input{runeCount: runeCount, matchIndex: matchIndex, validate: validate}
 }
 
+// Value returns the matched value of the Input.
+func (i *Input) Value() string {
+	return i.value
+}
+
+// Err returns the validation error of the Input.
+func (i *Input) Err() error {
+	return i.err
+}
+
+// Data returns the data of the Input.
+func (i *Input) Data() []Datum {
+	return i.data
+}
+
+// AddLines adds lines of additional information to the Input.
+func (i *Input) AddLines(lines ...string) {
+	i.lines = append(i.lines, lines...)
+}
+
+// AddData adds data to the Input.
+func (i *Input) AddData(data ...Datum) {
+	i.data = append(i.data, data...)
+}
+
 func (i *Input) validateValue() {
 	i.err, i.lines, i.data = i.validate(i.value, i.previousValues)
 }